@@ -0,0 +1,48 @@
+// Package anchor tracks anchored line ranges between commits. It is
+// shared by the commands and the TUI.
+package anchor
+
+import (
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+)
+
+// Track maps an anchor from the commit it was created on to another
+// commit. It returns nil if the anchored lines no longer exist, and
+// changed=true if some of them were modified.
+func Track(from, to string, a *remark.Anchor) (tracked *remark.Anchor, changed bool, err error) {
+	if from == to {
+		unchanged := *a
+		return &unchanged, false, nil
+	}
+
+	result, err := git.TrackLines(from, to, a.Path, a.StartLine, a.EndLine)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if result.Deleted {
+		return nil, false, nil
+	}
+
+	return &remark.Anchor{
+		Path:      result.Path,
+		StartLine: result.StartLine,
+		EndLine:   result.EndLine,
+	}, result.Changed, nil
+}
+
+// Retarget tracks an anchor to another commit for a remark moved or
+// copied there. It returns nil if there is no anchor or its lines do not
+// exist in the target commit.
+func Retarget(from, to string, a *remark.Anchor) *remark.Anchor {
+	if a == nil {
+		return nil
+	}
+
+	tracked, _, err := Track(from, to, a)
+	if err != nil {
+		return nil
+	}
+	return tracked
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,9 +14,12 @@ import (
 )
 
 var (
-	addType   string
-	addBranch string
-	addEdit   bool
+	addType       string
+	addBranch     string
+	addEdit       bool
+	addFile       string
+	addLines      string
+	addVisibility string
 )
 
 var addCmd = &cobra.Command{
@@ -26,10 +30,16 @@ var addCmd = &cobra.Command{
 If no commit is specified, the remark is added to HEAD.
 If no body is specified, your $EDITOR will open.
 
+Remarks are private unless added with --visibility shared (or with
+git config remarks.visibility shared). Shared remarks are also written
+to refs/notes/remarks-shared, which 'git remarks sync' pushes.
+
 Examples:
   git remarks add "This is a test helper, remove before PR"
   git remarks add --type todo "Refactor this later"
   git remarks add abc1234 "Note on older commit"
+  git remarks add --file auth/session.go --lines 40-62 "Is this lock needed?"
+  git remarks add --type decision --visibility shared "Use JWT for sessions"
   git remarks add  # opens editor`,
 	RunE: runAdd,
 }
@@ -38,6 +48,9 @@ func init() {
 	addCmd.Flags().StringVarP(&addType, "type", "t", "thought", "Remark type: thought, doubt, todo, decision")
 	addCmd.Flags().StringVarP(&addBranch, "branch", "b", "", "Override branch (for detached HEAD)")
 	addCmd.Flags().BoolVarP(&addEdit, "edit", "e", false, "Force open editor even if body provided")
+	addCmd.Flags().StringVarP(&addFile, "file", "f", "", "Anchor the remark to a file")
+	addCmd.Flags().StringVarP(&addLines, "lines", "l", "", "Anchor the remark to a line range in --file (e.g. 40-62)")
+	addCmd.Flags().StringVar(&addVisibility, "visibility", "", "Visibility: private, shared (default: private)")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -50,6 +63,11 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid type: %s (must be thought, doubt, todo, or decision)", addType)
 	}
 
+	visibility, err := resolveVisibility(addVisibility)
+	if err != nil {
+		return err
+	}
+
 	// Determine branch
 	branch := addBranch
 	if branch == "" {
@@ -92,6 +110,17 @@ func runAdd(cmd *cobra.Command, args []string) error {
 
 	shortSHA, _ := git.GetShortSHA(fullSHA)
 
+	// Anchor to a file and line range if requested
+	var anchor *remark.Anchor
+	if addFile != "" {
+		anchor, err = buildAnchor(fullSHA, addFile, addLines)
+		if err != nil {
+			return err
+		}
+	} else if addLines != "" {
+		return fmt.Errorf("--lines requires --file")
+	}
+
 	// Open editor if no body or --edit flag
 	if body == "" || addEdit {
 		editedBody, editedType, err := openEditor(shortSHA, branch, addType, body)
@@ -110,16 +139,86 @@ func runAdd(cmd *cobra.Command, args []string) error {
 
 	// Create and save remark
 	r := remark.NewRemark(remark.Type(addType), branch, body)
+	r.Author = git.GetUserIdent()
+	r.Anchor = anchor
+	if visibility == remark.VisibilityShared {
+		r.Visibility = visibility
+	}
 
 	s := store.New()
 	if err := s.Add(fullSHA, r); err != nil {
 		return fmt.Errorf("failed to add remark: %w", err)
 	}
 
+	if anchor != nil {
+		fmt.Printf("✓ Added remark [%s] to %s (%s) at %s\n", r.ID, shortSHA, r.Type, anchor)
+		return nil
+	}
+
 	fmt.Printf("✓ Added remark [%s] to %s (%s)\n", r.ID, shortSHA, r.Type)
 	return nil
 }
 
+// resolveVisibility returns the visibility given by a flag, falling back
+// to git config remarks.visibility and then private
+func resolveVisibility(flag string) (remark.Visibility, error) {
+	visibility := flag
+	if visibility == "" {
+		visibility, _ = git.Run("config", "remarks.visibility")
+	}
+	if visibility == "" {
+		return remark.VisibilityPrivate, nil
+	}
+	if !remark.ValidateVisibility(visibility) {
+		return "", fmt.Errorf("invalid visibility: %s (must be private or shared)", visibility)
+	}
+	return remark.Visibility(visibility), nil
+}
+
+// buildAnchor validates a file and optional line range against the
+// content of the file at the given commit
+func buildAnchor(commit, file, lines string) (*remark.Anchor, error) {
+	filePath, err := git.RepoPath(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %s: %w", file, err)
+	}
+
+	content, err := git.ReadFileAt(commit, filePath)
+	if errors.Is(err, git.ErrNotFile) {
+		return nil, fmt.Errorf("%s is not a file in %s", filePath, commit[:7])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("file %s does not exist in %s", filePath, commit[:7])
+	}
+
+	anchor := &remark.Anchor{Path: filePath}
+	if lines == "" {
+		return anchor, nil
+	}
+
+	start, end, err := remark.ParseLineRange(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	lineCount := len(splitLines(content))
+	if end > lineCount {
+		return nil, fmt.Errorf("line range %s is outside %s (%d lines)", lines, filePath, lineCount)
+	}
+
+	anchor.StartLine = start
+	anchor.EndLine = end
+	return anchor, nil
+}
+
+// splitLines splits file content into lines, ignoring the final newline
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
 func looksLikeCommit(s string) bool {
 	// Check if it's a valid git ref
 	_, err := git.Run("rev-parse", "--verify", s+"^{commit}")
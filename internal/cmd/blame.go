@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/anchor"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var blameCmd = &cobra.Command{
+	Use:   "blame <file>",
+	Short: "Annotate a file with the remarks that touch it",
+	Long: `Show a file as of HEAD with a gutter of remark IDs, followed by the
+remarks themselves.
+
+A line is annotated with the remarks on the commit that last changed it
+(as reported by git blame) and with any remark anchored to it. Anchors
+are tracked from their commit to HEAD.
+
+Examples:
+  git remarks blame internal/auth/session.go`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBlame,
+}
+
+// blameRemark is a remark shown in the blame footer
+type blameRemark struct {
+	Commit string
+	Remark remark.Remark
+	Where  string // anchored location in HEAD, if any
+	Line   int    // first anchored line in HEAD
+}
+
+func runBlame(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	filePath, err := git.RepoPath(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %w", args[0], err)
+	}
+
+	head, err := git.GetHEAD()
+	if err != nil {
+		return fmt.Errorf("cannot get HEAD: %w", err)
+	}
+
+	lines, err := git.Blame(head, filePath)
+	if err != nil {
+		return fmt.Errorf("cannot blame %s: %w", filePath, err)
+	}
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil && !errors.Is(err, git.ErrDetachedHead) {
+		return fmt.Errorf("cannot determine current branch: %w", err)
+	}
+
+	s := store.New()
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	gutter := make([][]string, len(lines)+1) // remark IDs per line number
+	var footer []blameRemark
+	seen := make(map[string]bool)
+
+	// Remarks on the commits that last touched each line
+	for _, line := range lines {
+		remarks, ok := allRemarks[line.Commit]
+		if !ok {
+			continue
+		}
+		for _, r := range activeRemarks(remarks, branch) {
+			if r.Anchor != nil {
+				continue // placed by its anchor below
+			}
+			gutter[line.Line] = append(gutter[line.Line], r.ID)
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				footer = append(footer, blameRemark{Commit: line.Commit, Remark: r})
+			}
+		}
+	}
+
+	// Remarks anchored to this file, tracked to HEAD
+	var anchored []blameRemark
+	for commit, remarks := range allRemarks {
+		for _, r := range activeRemarks(remarks, branch) {
+			if r.Anchor == nil {
+				continue
+			}
+			tracked, _, err := anchor.Track(commit, head, r.Anchor)
+			if err != nil || tracked == nil || tracked.Path != filePath {
+				continue
+			}
+			if tracked.HasLines() {
+				for n := tracked.StartLine; n <= tracked.EndLine && n < len(gutter); n++ {
+					gutter[n] = append(gutter[n], r.ID)
+				}
+			}
+			anchored = append(anchored, blameRemark{Commit: commit, Remark: r, Where: tracked.String(), Line: tracked.StartLine})
+		}
+	}
+
+	sort.Slice(anchored, func(i, j int) bool {
+		return anchored[i].Line < anchored[j].Line
+	})
+	footer = append(footer, anchored...)
+
+	printBlame(lines, gutter)
+
+	if len(footer) == 0 {
+		fmt.Printf("\n%s (no remarks)\n", filePath)
+		return nil
+	}
+
+	fmt.Printf("\n%s (%d remark%s)\n\n", filePath, len(footer), pluralize(len(footer)))
+	for _, f := range footer {
+		shortSHA, _ := git.GetShortSHA(f.Commit)
+		where := ""
+		if f.Where != "" {
+			where = " · " + f.Where
+		}
+		label := fmt.Sprintf("[%s] %s", f.Remark.ID, f.Remark.Type)
+		fmt.Printf("%s · %s · %s%s\n", colorize(typeColor(f.Remark.Type), label), formatAge(f.Remark.CreatedAt), shortSHA, where)
+		printBody(f.Remark.Body)
+	}
+
+	return nil
+}
+
+// printBlame prints the file with a gutter of remark IDs per line
+func printBlame(lines []git.BlameLine, gutter [][]string) {
+	idWidth := 0
+	for _, ids := range gutter {
+		if w := len(gutterLabel(ids)); w > idWidth {
+			idWidth = w
+		}
+	}
+	numWidth := len(fmt.Sprint(len(lines)))
+
+	for _, line := range lines {
+		label := fmt.Sprintf("%-*s", idWidth, gutterLabel(gutter[line.Line]))
+		if len(gutter[line.Line]) > 0 {
+			label = colorize(colorYellow, label)
+		}
+		fmt.Printf("%s %s %s\n", label, colorize(colorDim, fmt.Sprintf("%*d │", numWidth, line.Line)), line.Content)
+	}
+}
+
+// gutterLabel shows the first remark ID on a line and how many more there are
+func gutterLabel(ids []string) string {
+	switch len(ids) {
+	case 0:
+		return ""
+	case 1:
+		return ids[0]
+	default:
+		return fmt.Sprintf("%s +%d", ids[0], len(ids)-1)
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	changelogStyle    string
+	changelogTemplate string
+	changelogOutput   string
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog <revision-range>",
+	Short: "Generate a decision log from decision remarks",
+	Long: `Collect every decision remark on commits in a revision range, on
+all branches and including resolved ones, and render them as Markdown.
+
+Styles:
+  changelog  a "Decisions" section for release notes (default)
+  adr        one ADR-style record per decision with date, commit,
+             context and rationale
+
+The output can be customised with a Go text/template passed with
+--template or set with git config remarks.changelog.template. The
+template receives .Range and .Decisions; each decision has .ID, .Body,
+.Commit, .ShortSHA, .Subject, .Anchor, .Author, .State and .CreatedAt.
+
+Examples:
+  git remarks changelog v1.2..v1.3
+  git remarks changelog v1.2..v1.3 --style adr -o docs/decisions/v1.3.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runChangelog,
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogStyle, "style", "changelog", "Output style: changelog, adr")
+	changelogCmd.Flags().StringVar(&changelogTemplate, "template", "", "Template file (overrides --style)")
+	changelogCmd.Flags().StringVarP(&changelogOutput, "output", "o", "", "Write to file instead of stdout")
+}
+
+const changelogSectionTemplate = `## Decisions ({{.Range}})
+{{range .Decisions}}
+- {{indent 2 .Body}} ({{.ShortSHA}} {{.Subject}}, {{date .CreatedAt}})
+{{- else}}
+No decisions recorded.
+{{- end}}
+`
+
+const changelogADRTemplate = `# Decision log ({{.Range}})
+{{range $i, $d := .Decisions}}
+## {{inc $i}}. {{firstLine $d.Body}}
+
+- **Date:** {{date $d.CreatedAt}}
+- **Commit:** {{$d.ShortSHA}} {{$d.Subject}}
+{{- if $d.Anchor}}
+- **Location:** {{$d.Anchor}}
+{{- end}}
+{{- if $d.Author}}
+- **Author:** {{$d.Author}}
+{{- end}}
+- **Status:** {{if eq $d.State "resolved"}}superseded{{else}}accepted{{end}}
+
+{{$d.Body}}
+{{else}}
+No decisions recorded.
+{{end}}`
+
+// changelogData is passed to the changelog template
+type changelogData struct {
+	Range     string
+	Decisions []templateItem
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	var defaultTemplate string
+	switch changelogStyle {
+	case "changelog":
+		defaultTemplate = changelogSectionTemplate
+	case "adr":
+		defaultTemplate = changelogADRTemplate
+	default:
+		return fmt.Errorf("invalid style: %s (must be changelog or adr)", changelogStyle)
+	}
+
+	filter := &remarkFilter{
+		AllBranches: true,
+		Types:       []string{string(remark.TypeDecision)},
+		State:       "all",
+		Range:       args[0],
+	}
+	entries, err := collectRemarks(store.New(), filter)
+	if err != nil {
+		return err
+	}
+
+	// Oldest first, so the log reads in the order decisions were made
+	sortEntries(entries, "commit", true)
+
+	data := changelogData{Range: args[0]}
+	for _, e := range entries {
+		data.Decisions = append(data.Decisions, newTemplateItem(e))
+	}
+
+	output, err := renderTemplate("changelog", defaultTemplate, changelogTemplate, "remarks.changelog.template", data)
+	if err != nil {
+		return err
+	}
+
+	if changelogOutput == "" {
+		fmt.Print(output)
+		return nil
+	}
+
+	if err := os.WriteFile(changelogOutput, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", changelogOutput, err)
+	}
+	fmt.Fprintf(os.Stderr, "✓ Wrote %d decision%s to %s\n", len(data.Decisions), pluralize(len(data.Decisions)), changelogOutput)
+	return nil
+}
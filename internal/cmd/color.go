@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Enigama/git-remarks/internal/format"
+	"github.com/Enigama/git-remarks/internal/remark"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorDim    = "\033[2m"
+	colorRed    = format.Red
+	colorGreen  = format.Green
+	colorYellow = format.Yellow
+	colorBlue   = "\033[34m"
+	colorCyan   = format.Cyan
+)
+
+// useColor reports whether stdout is a terminal and NO_COLOR is unset
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps s in the given color when color output is enabled
+func colorize(color, s string) string {
+	if !useColor() {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// typeColor returns the color used for a remark type
+func typeColor(t remark.Type) string {
+	return format.TypeColor(t)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	commitMessages []string
+	commitRemarks  []string
+)
+
+var commitCmd = &cobra.Command{
+	Use:   "commit [-- git-commit-args...]",
+	Short: "Commit and attach remarks to the new commit",
+	Long: `Run 'git commit' and attach remarks to the commit it creates.
+
+Each --remark adds one remark. Prefix it with a type and a colon to set
+the type (thought by default). Lines of the form "Remark(todo): ..." in
+a -m message are turned into remarks too, and removed from the message.
+
+Arguments after -- are passed to 'git commit'.
+
+With 'git remarks init --commit-msg', "Remark(type): ..." lines work in
+any commit message, including one written in the editor.
+
+Examples:
+  git remarks commit -m "Add retry logic" --remark "todo: tune the backoff"
+  git remarks commit -m "Add retry logic" -m "Remark(doubt): is 3 retries enough?"
+  git remarks commit --remark "decision: keep the sync API" -- -a`,
+	SilenceUsage: true,
+	RunE:         runCommit,
+}
+
+func init() {
+	commitCmd.Flags().StringArrayVarP(&commitMessages, "message", "m", nil, "Commit message (repeat for more paragraphs)")
+	commitCmd.Flags().StringArrayVar(&commitRemarks, "remark", nil, "Remark to attach, optionally prefixed with its type (e.g. \"todo: ...\")")
+}
+
+func runCommit(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	var remarks []remark.MessageRemark
+	for _, text := range commitRemarks {
+		r, err := parseRemarkFlag(text)
+		if err != nil {
+			return err
+		}
+		remarks = append(remarks, r)
+	}
+
+	commitArgs := []string{"commit"}
+	for _, message := range commitMessages {
+		cleaned, found, err := remark.ExtractFromMessage(message)
+		if err != nil {
+			return err
+		}
+		remarks = append(remarks, found...)
+		if found != nil && strings.TrimSpace(cleaned) == "" {
+			continue // The paragraph held only remarks
+		}
+		commitArgs = append(commitArgs, "-m", strings.TrimRight(cleaned, "\n"))
+	}
+	commitArgs = append(commitArgs, args...)
+
+	before, _ := git.GetHEAD()
+	if err := git.RunInteractive(commitArgs...); err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+
+	after, err := git.GetHEAD()
+	if err != nil || after == before {
+		return fmt.Errorf("git commit did not create a commit")
+	}
+
+	if len(remarks) == 0 {
+		return nil
+	}
+	return addMessageRemarks(after, remarks)
+}
+
+// parseRemarkFlag parses a --remark value, where an optional leading
+// "type:" sets the remark type
+func parseRemarkFlag(text string) (remark.MessageRemark, error) {
+	r := remark.MessageRemark{Type: remark.TypeThought, Body: strings.TrimSpace(text)}
+	if prefix, body, ok := strings.Cut(text, ":"); ok && remark.ValidateType(strings.TrimSpace(prefix)) {
+		r.Type = remark.Type(strings.TrimSpace(prefix))
+		r.Body = strings.TrimSpace(body)
+	}
+
+	if r.Body == "" {
+		return r, fmt.Errorf("remark body cannot be empty")
+	}
+	return r, nil
+}
+
+// addMessageRemarks attaches remarks written with a commit to it, as
+// if each had been added with 'git remarks add'
+func addMessageRemarks(commit string, remarks []remark.MessageRemark) error {
+	fullSHA, err := git.Run("rev-parse", commit)
+	if err != nil {
+		return fmt.Errorf("invalid commit: %s", commit)
+	}
+	shortSHA, _ := git.GetShortSHA(fullSHA)
+
+	branch := commitBranch()
+	visibility, err := resolveVisibility("")
+	if err != nil {
+		return err
+	}
+
+	s := store.New()
+	existing, err := s.Get(fullSHA)
+	if err != nil {
+		return fmt.Errorf("failed to get remarks: %w", err)
+	}
+
+	var added []remark.Remark
+	for _, mr := range remarks {
+		r := remark.NewRemark(mr.Type, branch, mr.Body)
+		r.Author = git.GetUserIdent()
+		if visibility == remark.VisibilityShared {
+			r.Visibility = visibility
+		}
+		existing.Add(r)
+		added = append(added, r)
+	}
+
+	if err := s.Save(fullSHA, existing); err != nil {
+		return fmt.Errorf("failed to add remarks: %w", err)
+	}
+
+	for _, r := range added {
+		fmt.Printf("✓ Added remark [%s] to %s (%s)\n", r.ID, shortSHA, r.Type)
+	}
+	return nil
+}
+
+// commitBranch returns the branch a new commit is made on, including
+// the branch being rebased when a rebase commits on a detached HEAD.
+// It is empty for a commit made on any other detached HEAD.
+func commitBranch() string {
+	branch, err := git.GetCurrentBranch()
+	if errors.Is(err, git.ErrDetachedHead) {
+		branch, err = git.GetRebaseBranch()
+	}
+	if err != nil {
+		return ""
+	}
+	return branch
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"gopkg.in/yaml.v3"
+)
+
+// pendingRemarksFile holds the remarks taken out of a commit message
+// by the commit-msg hook until post-commit attaches them
+const pendingRemarksFile = "REMARKS_PENDING"
+
+// unattachedRemarksFile collects the remarks of commit messages that
+// could not be attached to their commit, so they are never lost
+const unattachedRemarksFile = "REMARKS_UNATTACHED"
+
+// scissorsLine is where 'git commit --verbose' cuts the message
+const scissorsLine = "------------------------ >8 ------------------------"
+
+// pendingRemarks are the remarks of a commit that is being made. Head
+// (the commit HEAD was on) and the hashes of the message as each git
+// cleanup mode would commit it identify the commit they belong to.
+type pendingRemarks struct {
+	Head          string                 `yaml:"head"`
+	MessageHashes []string               `yaml:"message_hashes"`
+	Remarks       []remark.MessageRemark `yaml:"remarks"`
+}
+
+var commitMsgCmd = &cobra.Command{
+	Use:          "commit-msg <message-file>",
+	Short:        "Take remarks out of a commit message (internal command)",
+	Hidden:       true,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runCommitMsg,
+}
+
+var postCommitCmd = &cobra.Command{
+	Use:          "post-commit",
+	Short:        "Attach remarks taken from the commit message (internal command)",
+	Hidden:       true,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runPostCommit,
+}
+
+func runCommitMsg(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return nil // Silently exit if not in a git repo
+	}
+
+	path, err := git.Run("rev-parse", "--git-path", pendingRemarksFile)
+	if err != nil {
+		return fmt.Errorf("failed to get git directory: %w", err)
+	}
+	// post-commit never ran for the commit these were taken from
+	if stale, err := takePendingRemarks(path); err != nil {
+		return err
+	} else if stale != nil {
+		saveUnattachedRemarks(stale.Remarks, "from a commit that was aborted or made without post-commit")
+	}
+
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read commit message: %w", err)
+	}
+
+	message, remarks, err := remark.ExtractFromMessage(string(content))
+	if err != nil {
+		return err
+	}
+	if len(remarks) == 0 {
+		return nil
+	}
+
+	if isMergeCommand() {
+		// git merge runs commit-msg but not post-commit, so the remarks
+		// could not be attached: keep them in the message
+		n := len(remarks)
+		fmt.Fprintf(os.Stderr, "git-remarks: left %d Remark line%s in the merge commit message; git merge does not run post-commit\n", n, pluralize(n))
+		return nil
+	}
+
+	hashes, empty, err := messageHashes(message)
+	if err != nil {
+		return err
+	}
+	if empty {
+		return fmt.Errorf("the commit message has only Remark lines; add a subject")
+	}
+
+	head, _ := git.Run("rev-parse", "--verify", "--quiet", "HEAD")
+	data, err := yaml.Marshal(pendingRemarks{Head: head, MessageHashes: hashes, Remarks: remarks})
+	if err != nil {
+		return fmt.Errorf("failed to encode remarks: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(args[0], []byte(message), 0644); err != nil {
+		return fmt.Errorf("failed to write commit message: %w", err)
+	}
+	return nil
+}
+
+func runPostCommit(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return nil // Silently exit if not in a git repo
+	}
+
+	path, err := git.Run("rev-parse", "--git-path", pendingRemarksFile)
+	if err != nil {
+		return fmt.Errorf("failed to get git directory: %w", err)
+	}
+	pending, err := takePendingRemarks(path)
+	if err != nil || pending == nil {
+		return err
+	}
+
+	// The commit-msg hook is skipped by --no-verify, so the remarks may
+	// belong to an earlier, aborted commit
+	if !pending.matchesHEAD() {
+		saveUnattachedRemarks(pending.Remarks, "from a commit message that does not match HEAD")
+		return nil
+	}
+
+	if err := addMessageRemarks("HEAD", pending.Remarks); err != nil {
+		// The Remark lines are already gone from the message
+		saveUnattachedRemarks(pending.Remarks, fmt.Sprintf("from the commit (%v)", err))
+	}
+	return nil
+}
+
+// takePendingRemarks reads and removes the pending remarks file, and
+// returns nil if there is none
+func takePendingRemarks(path string) (*pendingRemarks, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+
+	var pending pendingRemarks
+	if err := yaml.Unmarshal(content, &pending); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &pending, nil
+}
+
+// matchesHEAD reports whether HEAD is the commit the remarks were taken
+// from: it was made on top of pending.Head, with the same message
+func (p *pendingRemarks) matchesHEAD() bool {
+	previous, _ := git.Run("rev-parse", "--verify", "--quiet", "HEAD@{1}")
+	if previous != p.Head {
+		return false
+	}
+
+	message, err := git.Run("log", "-1", "--format=%B", "HEAD")
+	if err != nil {
+		return false
+	}
+	for _, hash := range p.MessageHashes {
+		if hash == hashMessage(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// messageHashes returns the hashes of a commit message as git commits it
+// with each cleanup mode (strip, whitespace, verbatim), and whether git
+// will find it empty and abort the commit
+func messageHashes(message string) ([]string, bool, error) {
+	cut := message
+	if i := strings.Index(cut, scissorsLine); i >= 0 {
+		cut = cut[:strings.LastIndex(cut[:i], "\n")+1]
+	}
+
+	stripped, err := git.RunWithStdin(cut, "stripspace", "--strip-comments")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to clean up commit message: %w", err)
+	}
+	whitespace, err := git.RunWithStdin(message, "stripspace")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to clean up commit message: %w", err)
+	}
+
+	hashes := []string{hashMessage(stripped), hashMessage(whitespace), hashMessage(message)}
+	if stripsComments() {
+		return hashes, strings.TrimSpace(stripped) == "", nil
+	}
+	return hashes, strings.TrimSpace(whitespace) == "", nil
+}
+
+// stripsComments reports whether git will remove comment lines from the
+// message: by default only when it was written in an editor. Git runs
+// hooks with GIT_EDITOR=: when no editor is used.
+func stripsComments() bool {
+	switch mode, _ := git.Run("config", "commit.cleanup"); mode {
+	case "strip":
+		return true
+	case "whitespace", "verbatim", "scissors":
+		return false
+	}
+	return os.Getenv("GIT_EDITOR") != ":"
+}
+
+// hashMessage hashes a commit message, ignoring surrounding whitespace
+func hashMessage(message string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(message)))
+	return hex.EncodeToString(sum[:])
+}
+
+// isMergeCommand reports whether the commit is being made by 'git merge'
+// or 'git pull', which do not run post-commit
+func isMergeCommand() bool {
+	action := os.Getenv("GIT_REFLOG_ACTION")
+	return strings.HasPrefix(action, "merge") || (strings.HasPrefix(action, "pull") && !strings.Contains(action, "rebase"))
+}
+
+// saveUnattachedRemarks appends remarks that could not be attached to
+// their commit to the unattached remarks file, and says so
+func saveUnattachedRemarks(remarks []remark.MessageRemark, reason string) {
+	if len(remarks) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: not attached, %s\n", time.Now().Format(time.RFC3339), reason)
+	for _, r := range remarks {
+		b.WriteString(r.String() + "\n")
+	}
+
+	n := len(remarks)
+	fmt.Fprintf(os.Stderr, "git-remarks: %d remark%s %s %s not attached:\n", n, pluralize(n), reason, pluralizeWord(n, "was", "were"))
+	for _, r := range remarks {
+		fmt.Fprintf(os.Stderr, "  %s\n", r)
+	}
+
+	path, err := git.Run("rev-parse", "--git-path", unattachedRemarksFile)
+	if err == nil {
+		var f *os.File
+		if f, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+			_, err = f.WriteString(b.String())
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "git-remarks: failed to save them: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "  Saved in %s; add them with 'git remarks add'\n", path)
+}
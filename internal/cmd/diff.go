@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	diffAllBranches bool
+	diffNotes1      string
+	diffNotes2      string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <ref1> <ref2>",
+	Short: "Compare remarks between two branches or refs",
+	Long: `Compare the remarks on two refs, matching them by ID.
+
+Each side is made of the remarks on the commits since the merge-base of
+the two refs, or since the main branch if both refs are the same commit.
+When the refs name local branches (including reflog entries such as
+feature/x@{1}), only remarks scoped to one of those branches are used,
+on both sides.
+
+A side that is a reflog entry reads the notes as they were at the time of
+that entry, so remarks moved or edited since then show up as changed.
+Other sides read the current notes, unless --notes1 or --notes2 names a
+notes commit to read instead (e.g. refs/notes/remarks@{2.days.ago}).
+
+Remarks are reported as removed (only on ref1), added (only on ref2),
+changed (different commit, type, state, branch, body or anchor) or
+duplicated (the same ID on several commits of one side).
+
+Examples:
+  git remarks diff feature/a feature/b
+  git remarks diff feature/x@{1} feature/x
+  git remarks diff main feature/x --all-branches
+  git remarks diff feature/x feature/x --notes1 refs/notes/remarks@{1}`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffAllBranches, "all-branches", false, "Ignore branch scopes and compare all remarks")
+	diffCmd.Flags().StringVar(&diffNotes1, "notes1", "", "Notes commit to read the remarks of ref1 from")
+	diffCmd.Flags().StringVar(&diffNotes2, "notes2", "", "Notes commit to read the remarks of ref2 from")
+}
+
+// diffSide holds the remarks found on one side of a comparison
+type diffSide struct {
+	Ref        string
+	Branch     string // branch the ref names, if any
+	Notes      string // where the remarks were read from
+	Remarks    map[string]listEntry
+	Duplicates map[string][]string // remark ID -> short SHAs
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	sha1, err := git.Run("rev-parse", "--verify", "--quiet", args[0]+"^{commit}")
+	if err != nil || sha1 == "" {
+		return fmt.Errorf("invalid ref: %s", args[0])
+	}
+	sha2, err := git.Run("rev-parse", "--verify", "--quiet", args[1]+"^{commit}")
+	if err != nil || sha2 == "" {
+		return fmt.Errorf("invalid ref: %s", args[1])
+	}
+
+	base, err := git.Run("merge-base", sha1, sha2)
+	if err != nil {
+		return fmt.Errorf("%s and %s have no common history", args[0], args[1])
+	}
+	if sha1 == sha2 {
+		// Comparing two notes versions of one ref: use its own commits
+		if defaultBase, err := git.GetDefaultBase(); err == nil {
+			base = defaultBase
+		}
+	}
+
+	// Both sides share one scope, so a remark is matched by ID even when
+	// only its branch changed
+	var scope map[string]bool
+	if !diffAllBranches {
+		for _, ref := range args {
+			if branch := branchForRef(ref); branch != "" {
+				if scope == nil {
+					scope = make(map[string]bool)
+				}
+				scope[branch] = true
+			}
+		}
+	}
+
+	s := store.New()
+	left, err := collectDiffSide(s, args[0], sha1, base, diffNotes1, scope)
+	if err != nil {
+		return err
+	}
+	right, err := collectDiffSide(s, args[1], sha2, base, diffNotes2, scope)
+	if err != nil {
+		return err
+	}
+
+	var removed, added, changed []string
+	for _, id := range sortedIDs(left.Remarks) {
+		l := left.Remarks[id]
+		r, ok := right.Remarks[id]
+		if !ok {
+			removed = append(removed, describeDiffEntry(l))
+			continue
+		}
+		if changes := remarkChanges(l, r); len(changes) > 0 {
+			changed = append(changed, fmt.Sprintf("[%s] %s", id, strings.Join(changes, ", ")))
+		}
+	}
+	for _, id := range sortedIDs(right.Remarks) {
+		if _, ok := left.Remarks[id]; !ok {
+			added = append(added, describeDiffEntry(right.Remarks[id]))
+		}
+	}
+
+	fmt.Printf("%s (%d remark%s) → %s (%d remark%s)\n",
+		left.label(), len(left.Remarks), pluralize(len(left.Remarks)),
+		right.label(), len(right.Remarks), pluralize(len(right.Remarks)))
+
+	if len(removed)+len(added)+len(changed)+len(left.Duplicates)+len(right.Duplicates) == 0 {
+		fmt.Println("\nNo differences")
+		return nil
+	}
+
+	printDiffSection(fmt.Sprintf("Removed (only on %s)", left.Ref), "-", colorRed, removed)
+	printDiffSection(fmt.Sprintf("Added (only on %s)", right.Ref), "+", colorGreen, added)
+	printDiffSection("Changed", "~", colorYellow, changed)
+	printDiffSection(fmt.Sprintf("Duplicated on %s", left.Ref), "!", colorYellow, describeDuplicates(left.Duplicates))
+	printDiffSection(fmt.Sprintf("Duplicated on %s", right.Ref), "!", colorYellow, describeDuplicates(right.Duplicates))
+
+	return nil
+}
+
+// collectDiffSide gathers the remarks on commits in base..sha whose
+// branch is in scope (all branches if scope is nil)
+func collectDiffSide(s *store.Store, ref, sha, base, notesRev string, scope map[string]bool) (*diffSide, error) {
+	side := &diffSide{
+		Ref:        ref,
+		Branch:     branchForRef(ref),
+		Remarks:    make(map[string]listEntry),
+		Duplicates: make(map[string][]string),
+	}
+
+	allRemarks, err := readDiffNotes(s, side, notesRev)
+	if err != nil {
+		return nil, err
+	}
+
+	if sha == base {
+		return side, nil
+	}
+
+	commits, err := git.GetLog([]string{base + ".." + sha}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w", ref, err)
+	}
+
+	for pos, c := range commits {
+		remarks, ok := allRemarks[c.SHA]
+		if !ok {
+			continue
+		}
+		for _, r := range remarks.Remarks {
+			if scope != nil && !scope[r.Branch] {
+				continue
+			}
+
+			if existing, seen := side.Remarks[r.ID]; seen {
+				if len(side.Duplicates[r.ID]) == 0 {
+					side.Duplicates[r.ID] = []string{existing.ShortSHA}
+				}
+				side.Duplicates[r.ID] = append(side.Duplicates[r.ID], c.ShortSHA)
+				continue
+			}
+
+			side.Remarks[r.ID] = listEntry{
+				Commit:   c.SHA,
+				ShortSHA: c.ShortSHA,
+				Subject:  c.Subject,
+				Remark:   r,
+				Position: pos,
+			}
+		}
+	}
+
+	return side, nil
+}
+
+// readDiffNotes returns the remarks a side is compared with: the notes
+// at notesRev if given, the notes at the time of the ref's reflog entry,
+// or the current notes
+func readDiffNotes(s *store.Store, side *diffSide, notesRev string) (map[string]*remark.Remarks, error) {
+	if notesRev != "" {
+		notes, err := store.ListAt(notesRev)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notes commit: %s", notesRev)
+		}
+		side.Notes = "notes at " + notesRev
+		return notes, nil
+	}
+
+	if when, ok := git.GetReflogTime(side.Ref); ok {
+		notes, err := s.ListAllAt(when)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read remarks at %s: %w", side.Ref, err)
+		}
+		side.Notes = "notes as of " + when
+		if secs, err := strconv.ParseInt(strings.TrimPrefix(when, "@"), 10, 64); err == nil {
+			side.Notes = "notes as of " + time.Unix(secs, 0).Format("2006-01-02 15:04")
+		}
+		return notes, nil
+	}
+
+	notes, err := s.ListAllWithRemarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remarks: %w", err)
+	}
+	return notes, nil
+}
+
+func (d *diffSide) label() string {
+	var details []string
+	if d.Branch != "" && d.Branch != d.Ref {
+		details = append(details, d.Branch)
+	}
+	if d.Notes != "" {
+		details = append(details, d.Notes)
+	}
+	if len(details) == 0 {
+		return d.Ref
+	}
+	return fmt.Sprintf("%s [%s]", d.Ref, strings.Join(details, ", "))
+}
+
+// branchForRef returns the local branch a ref refers to, ignoring reflog
+// and ancestry suffixes, or "" if it does not name a branch
+func branchForRef(ref string) string {
+	name := ref
+	if i := strings.IndexAny(name, "@~^"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		return ""
+	}
+
+	if _, err := git.Run("show-ref", "--verify", "--quiet", "refs/heads/"+name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// remarkChanges lists what differs between two versions of a remark
+func remarkChanges(a, b listEntry) []string {
+	var changes []string
+
+	if a.Commit != b.Commit {
+		kind := "moved"
+		if samePatch(a.Commit, b.Commit) {
+			kind = "rewritten"
+		}
+		changes = append(changes, fmt.Sprintf("%s %s → %s", kind, a.ShortSHA, b.ShortSHA))
+	}
+	if a.Remark.Type != b.Remark.Type {
+		changes = append(changes, fmt.Sprintf("type %s → %s", a.Remark.Type, b.Remark.Type))
+	}
+	if a.Remark.State != b.Remark.State {
+		changes = append(changes, fmt.Sprintf("state %s → %s", a.Remark.State, b.Remark.State))
+	}
+	if a.Remark.Branch != b.Remark.Branch {
+		changes = append(changes, fmt.Sprintf("branch %s → %s", a.Remark.Branch, b.Remark.Branch))
+	}
+	if strings.TrimSpace(a.Remark.Body) != strings.TrimSpace(b.Remark.Body) {
+		changes = append(changes, "body edited")
+	}
+	if anchorString(a.Remark.Anchor) != anchorString(b.Remark.Anchor) {
+		changes = append(changes, fmt.Sprintf("anchor %s → %s", anchorString(a.Remark.Anchor), anchorString(b.Remark.Anchor)))
+	}
+
+	return changes
+}
+
+// samePatch returns true if two commits have the same patch-id
+func samePatch(a, b string) bool {
+	pa, err := git.GetPatchID(a)
+	if err != nil || pa == "" {
+		return false
+	}
+	pb, err := git.GetPatchID(b)
+	return err == nil && pa == pb
+}
+
+func anchorString(a *remark.Anchor) string {
+	if a == nil {
+		return "none"
+	}
+	return a.String()
+}
+
+func describeDiffEntry(e listEntry) string {
+	return fmt.Sprintf("[%s] %s · %s %s · %s", e.Remark.ID, e.Remark.Type, e.ShortSHA, e.Subject, firstLine(e.Remark.Body))
+}
+
+func describeDuplicates(duplicates map[string][]string) []string {
+	ids := make([]string, 0, len(duplicates))
+	for id := range duplicates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	lines := make([]string, 0, len(ids))
+	for _, id := range ids {
+		lines = append(lines, fmt.Sprintf("[%s] on %s", id, strings.Join(duplicates[id], ", ")))
+	}
+	return lines
+}
+
+func printDiffSection(title, marker, color string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", title)
+	for _, line := range lines {
+		fmt.Printf("  %s %s\n", colorize(color, marker), line)
+	}
+}
+
+// sortedIDs returns the IDs of a side in history order
+func sortedIDs(entries map[string]listEntry) []string {
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := entries[ids[i]], entries[ids[j]]
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
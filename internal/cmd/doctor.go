@@ -0,0 +1,515 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/hook"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the hook installation and remark store for problems",
+	Long: `Diagnose the git-remarks installation and remark store.
+
+Checks that the post-rewrite hook is installed where git will run it
+(honouring core.hooksPath), is executable and calls migrate-rewrites,
+that optional hooks installed with --pre-push or --commit-msg are up to
+date, that git-remarks is on PATH for the hooks, and reports orphaned
+remarks and remarks scoped to branches that no longer exist.
+
+Each problem comes with a suggested fix. With --fix, problems that can
+be fixed automatically are fixed.
+
+Examples:
+  git remarks doctor
+  git remarks doctor --fix`,
+	SilenceUsage: true,
+	RunE:         runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Apply the fixes that can be made automatically")
+}
+
+// doctorStatus is the outcome of a single check
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is the result of one diagnostic
+type doctorCheck struct {
+	Status doctorStatus
+	Title  string
+	Detail string
+	// Suggestion is a fix the user can apply by hand
+	Suggestion string
+	// Fix applies the fix automatically, if possible
+	Fix func() (string, error)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	checks, err := hookChecks()
+	if err != nil {
+		return err
+	}
+	optional, err := optionalHookChecks()
+	if err != nil {
+		return err
+	}
+	checks = append(checks, optional...)
+	checks = append(checks, binaryCheck())
+
+	storeChecks, err := remarkStoreChecks(store.New())
+	if err != nil {
+		return err
+	}
+	checks = append(checks, storeChecks...)
+
+	problems, fixed := 0, 0
+	for _, c := range checks {
+		if c.Status != doctorOK && doctorFix && c.Fix != nil {
+			message, err := c.Fix()
+			if err != nil {
+				printDoctorCheck(c)
+				fmt.Printf("    Fix failed: %v\n", err)
+				problems++
+				continue
+			}
+			fmt.Printf("%s %s\n", colorize(colorGreen, "✓"), message)
+			fixed++
+			continue
+		}
+
+		printDoctorCheck(c)
+		if c.Status != doctorOK {
+			problems++
+		}
+	}
+
+	fmt.Println()
+	if fixed > 0 {
+		fmt.Printf("Fixed %d problem%s\n", fixed, pluralize(fixed))
+	}
+	if problems == 0 {
+		fmt.Println("No problems found")
+		return nil
+	}
+
+	fixable := 0
+	for _, c := range checks {
+		if c.Status != doctorOK && c.Fix != nil {
+			fixable++
+		}
+	}
+	if !doctorFix && fixable > 0 {
+		fmt.Printf("Run 'git remarks doctor --fix' to fix %d of them automatically\n", fixable)
+	}
+	return fmt.Errorf("%d problem%s found", problems, pluralize(problems))
+}
+
+func printDoctorCheck(c doctorCheck) {
+	switch c.Status {
+	case doctorOK:
+		fmt.Printf("%s %s\n", colorize(colorGreen, "✓"), c.Title)
+	case doctorWarn:
+		fmt.Printf("%s %s\n", colorize(colorYellow, "!"), c.Title)
+	default:
+		fmt.Printf("%s %s\n", colorize(colorRed, "✗"), c.Title)
+	}
+
+	if c.Detail != "" {
+		fmt.Printf("    %s\n", colorize(colorDim, c.Detail))
+	}
+	if c.Status != doctorOK && c.Suggestion != "" {
+		fmt.Printf("    Fix: %s\n", c.Suggestion)
+	}
+}
+
+// hookChecks verifies the post-rewrite hook in the directory git runs
+// hooks from
+func hookChecks() ([]doctorCheck, error) {
+	hooksDir, err := git.GetHooksDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hooks directory: %w", err)
+	}
+
+	var checks []doctorCheck
+	if hooksPath, _ := git.Run("config", "core.hooksPath"); hooksPath != "" {
+		checks = append(checks, doctorCheck{
+			Status: doctorOK,
+			Title:  "core.hooksPath is set",
+			Detail: "git runs hooks from " + hooksDir,
+		})
+	}
+
+	hookPath := filepath.Join(hooksDir, "post-rewrite")
+	install := func(upgrade bool) func() (string, error) {
+		return installFix(hooksDir, postRewriteHook, upgrade)
+	}
+
+	status, err := hook.InspectFile(hookPath, hook.PostRewrite)
+	if err != nil {
+		return nil, err
+	}
+
+	var content []byte
+	if status != hook.StatusMissing {
+		content, err = os.ReadFile(hookPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read hook: %w", err)
+		}
+	}
+
+	// A hook manager owns the hook unless our block is in it directly
+	ownBlock := status == hook.StatusCurrent || status == hook.StatusOutdated || status == hook.StatusLegacy ||
+		status == hook.StatusManual && callsMigrateRewrites(string(content))
+	if !ownBlock {
+		root, err := git.GetRepoRoot()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repository root: %w", err)
+		}
+		if m, ok := hook.DetectManager(root); ok {
+			return append(checks, managerChecks(root, hooksDir, m)...), nil
+		}
+	}
+
+	if status == hook.StatusMissing {
+		return append(checks, doctorCheck{
+			Status:     doctorFail,
+			Title:      "post-rewrite hook is not installed",
+			Detail:     fmt.Sprintf("%s does not exist, so remarks are lost on rebase and amend", hookPath),
+			Suggestion: "git remarks init",
+			Fix:        install(false),
+		}), nil
+	}
+
+	switch {
+	case status == hook.StatusManual && !callsMigrateRewrites(string(content)):
+		checks = append(checks, doctorCheck{
+			Status:     doctorFail,
+			Title:      "post-rewrite hook does not call git-remarks migrate-rewrites",
+			Detail:     hookPath + " mentions git-remarks outside a managed block",
+			Suggestion: `add 'git-remarks migrate-rewrites "$1"' to the hook`,
+		})
+	case status == hook.StatusAbsent:
+		checks = append(checks, doctorCheck{
+			Status:     doctorFail,
+			Title:      "post-rewrite hook does not call git-remarks migrate-rewrites",
+			Detail:     hookPath,
+			Suggestion: "git remarks init (appends a git-remarks block to the hook)",
+			Fix:        install(false),
+		})
+	case status == hook.StatusOutdated, status == hook.StatusLegacy:
+		checks = append(checks, doctorCheck{
+			Status:     doctorWarn,
+			Title:      "post-rewrite hook is outdated",
+			Detail:     hookPath,
+			Suggestion: "git remarks init --upgrade",
+			Fix:        install(true),
+		})
+	default:
+		checks = append(checks, doctorCheck{
+			Status: doctorOK,
+			Title:  "post-rewrite hook installed",
+			Detail: hookPath,
+		})
+	}
+
+	info, err := os.Stat(hookPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook: %w", err)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		checks = append(checks, doctorCheck{
+			Status:     doctorFail,
+			Title:      "post-rewrite hook is not executable",
+			Detail:     "git ignores hooks without the executable bit",
+			Suggestion: "chmod +x " + hookPath,
+			Fix: func() (string, error) {
+				if err := os.Chmod(hookPath, info.Mode().Perm()|0111); err != nil {
+					return "", err
+				}
+				return "made " + hookPath + " executable", nil
+			},
+		})
+	}
+
+	return checks, nil
+}
+
+// optionalHookChecks verifies the hooks installed by init flags such as
+// --pre-push, when they are installed
+func optionalHookChecks() ([]doctorCheck, error) {
+	hooksDir, err := git.GetHooksDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hooks directory: %w", err)
+	}
+
+	statuses := make(map[string]hook.Status)
+	installed := make(map[string]bool)
+	for _, h := range managedHooks {
+		if h.Flag == "" {
+			continue
+		}
+		status, err := hook.InspectFile(filepath.Join(hooksDir, h.Name), h.Script)
+		if err != nil {
+			return nil, err
+		}
+		statuses[h.Name] = status
+		if status == hook.StatusCurrent || status == hook.StatusOutdated {
+			installed[h.Flag] = true
+		}
+	}
+
+	var checks []doctorCheck
+	for _, h := range managedHooks {
+		if h.Flag == "" {
+			continue
+		}
+		hookPath := filepath.Join(hooksDir, h.Name)
+
+		switch status := statuses[h.Name]; {
+		case status == hook.StatusCurrent:
+			checks = append(checks, doctorCheck{
+				Status: doctorOK,
+				Title:  h.Name + " hook installed",
+				Detail: hookPath,
+			})
+		case status == hook.StatusOutdated:
+			checks = append(checks, doctorCheck{
+				Status:     doctorWarn,
+				Title:      h.Name + " hook is outdated",
+				Detail:     hookPath,
+				Suggestion: initCommand(h, true),
+				Fix:        installFix(hooksDir, h, true),
+			})
+		case status == hook.StatusManual:
+		case installed[h.Flag]:
+			// The other hooks installed by the same flag need this one
+			checks = append(checks, doctorCheck{
+				Status:     doctorFail,
+				Title:      h.Name + " hook is not installed",
+				Detail:     fmt.Sprintf("the other hooks installed by '%s' need it", initCommand(h, false)),
+				Suggestion: initCommand(h, false),
+				Fix:        installFix(hooksDir, h, false),
+			})
+		case h.Name == prePushHook.Name:
+			if policy, _ := git.Run("config", "remarks.prePush"); policy == "block" {
+				check := doctorCheck{
+					Status:     doctorWarn,
+					Title:      "remarks.prePush is block but the pre-push hook is not installed",
+					Detail:     hookPath,
+					Suggestion: initCommand(h, false),
+					Fix:        installFix(hooksDir, h, false),
+				}
+				if status == hook.StatusAbsent {
+					// init leaves an existing pre-push hook alone
+					check.Suggestion = "add 'git-remarks pre-push \"$1\"' to the existing hook, with the same stdin"
+					check.Fix = nil
+				}
+				checks = append(checks, check)
+			}
+		}
+	}
+	return checks, nil
+}
+
+// installFix returns a fix that installs a hook in hooksDir
+func installFix(hooksDir string, h managedHook, upgrade bool) func() (string, error) {
+	return func() (string, error) {
+		message, err := installHook(hooksDir, h, upgrade)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimPrefix(message, "✓ ") + " in " + hooksDir, nil
+	}
+}
+
+// managerChecks verifies that a hook manager runs migrate-rewrites
+func managerChecks(root, hooksDir string, m hook.Manager) []doctorCheck {
+	var checks []doctorCheck
+	config := hook.ConfigPath(root, m)
+	if rel, err := filepath.Rel(root, config); err == nil {
+		config = rel
+	}
+
+	hasStep, err := hook.HasStep(root, m)
+	switch {
+	case err != nil:
+		checks = append(checks, doctorCheck{Status: doctorFail, Title: fmt.Sprintf("cannot read %s config", m), Detail: err.Error()})
+	case !hasStep:
+		checks = append(checks, doctorCheck{
+			Status:     doctorFail,
+			Title:      fmt.Sprintf("%s does not run git-remarks migrate-rewrites", m),
+			Detail:     config + " has no git-remarks step, so remarks are lost on rebase and amend",
+			Suggestion: "git remarks init --manager " + string(m),
+			Fix: func() (string, error) {
+				if _, err := hook.InstallManager(root, m, true); err != nil {
+					return "", err
+				}
+				return "added git-remarks step to " + config, nil
+			},
+		})
+	default:
+		checks = append(checks, doctorCheck{
+			Status: doctorOK,
+			Title:  fmt.Sprintf("%s runs git-remarks migrate-rewrites", m),
+			Detail: config,
+		})
+	}
+
+	if hook.RunsHooks(root, hooksDir, m) {
+		checks = append(checks, doctorCheck{
+			Status: doctorOK,
+			Title:  fmt.Sprintf("%s post-rewrite hook installed", m),
+			Detail: filepath.Join(hooksDir, "post-rewrite"),
+		})
+	} else {
+		checks = append(checks, doctorCheck{
+			Status:     doctorFail,
+			Title:      fmt.Sprintf("%s has not installed the post-rewrite hook", m),
+			Detail:     fmt.Sprintf("git runs hooks from %s", hooksDir),
+			Suggestion: hook.InstallCommand(m),
+		})
+	}
+	return checks
+}
+
+// callsMigrateRewrites returns true if a hook script runs migrate-rewrites
+func callsMigrateRewrites(content string) bool {
+	return strings.Contains(content, "git-remarks migrate-rewrites") ||
+		strings.Contains(content, "git remarks migrate-rewrites")
+}
+
+// binaryCheck verifies that the hook's `command -v git-remarks` test
+// finds this binary
+func binaryCheck() doctorCheck {
+	self, _ := os.Executable()
+	if self != "" {
+		if resolved, err := filepath.EvalSymlinks(self); err == nil {
+			self = resolved
+		}
+	}
+
+	found, err := exec.LookPath("git-remarks")
+	if err != nil {
+		check := doctorCheck{
+			Status: doctorFail,
+			Title:  "git-remarks is not on PATH",
+			Detail: "the hook skips migration when `command -v git-remarks` fails",
+		}
+		if self != "" {
+			check.Suggestion = fmt.Sprintf("add %s to PATH", filepath.Dir(self))
+		} else {
+			check.Suggestion = "install git-remarks into a directory on PATH"
+		}
+		return check
+	}
+
+	if resolved, err := filepath.EvalSymlinks(found); err == nil {
+		found = resolved
+	}
+	if self != "" && found != self {
+		return doctorCheck{
+			Status:     doctorWarn,
+			Title:      "hook runs a different git-remarks binary",
+			Detail:     fmt.Sprintf("PATH has %s, but this is %s", found, self),
+			Suggestion: "remove the outdated binary or reorder PATH",
+		}
+	}
+
+	return doctorCheck{
+		Status: doctorOK,
+		Title:  "git-remarks is on PATH",
+		Detail: found,
+	}
+}
+
+// remarkStoreChecks reports orphaned remarks and remarks scoped to
+// branches that no longer exist
+func remarkStoreChecks(s *store.Store) ([]doctorCheck, error) {
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	reachable, err := git.GetBranchCommits()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch commits: %w", err)
+	}
+
+	branches, err := git.ListBranches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	existing := make(map[string]bool)
+	for _, b := range branches {
+		existing[b] = true
+	}
+
+	orphanedRemarks, orphanedCommits := 0, 0
+	missing := make(map[string]int)
+	for commit, remarks := range allRemarks {
+		if !reachable[commit] {
+			orphanedCommits++
+			orphanedRemarks += len(remarks.Remarks)
+		}
+		for _, r := range activeRemarks(remarks, "") {
+			if r.Branch != "" && !existing[r.Branch] {
+				missing[r.Branch]++
+			}
+		}
+	}
+
+	var checks []doctorCheck
+
+	if orphanedCommits == 0 {
+		checks = append(checks, doctorCheck{Status: doctorOK, Title: "no orphaned remarks"})
+	} else {
+		checks = append(checks, doctorCheck{
+			Status: doctorWarn,
+			Title: fmt.Sprintf("%d orphaned remark%s on %d commit%s",
+				orphanedRemarks, pluralize(orphanedRemarks), orphanedCommits, pluralize(orphanedCommits)),
+			Detail:     "attached to commits no longer reachable from any branch",
+			Suggestion: "git remarks recover",
+		})
+	}
+
+	if len(missing) == 0 {
+		checks = append(checks, doctorCheck{Status: doctorOK, Title: "all remark branches exist"})
+		return checks, nil
+	}
+
+	names := make([]string, 0, len(missing))
+	for b := range missing {
+		names = append(names, b)
+	}
+	sort.Strings(names)
+
+	for _, b := range names {
+		checks = append(checks, doctorCheck{
+			Status:     doctorWarn,
+			Title:      fmt.Sprintf("%d active remark%s on deleted branch %s", missing[b], pluralize(missing[b]), b),
+			Suggestion: fmt.Sprintf("git remarks migrate-branch %s <new-branch>, or git remarks resolve --branch %s --all-on-branch", b, b),
+		})
+	}
+	return checks, nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+	exportFilter remarkFilter
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export remarks to Markdown, JSON or CSV",
+	Long: `Export remarks matching the same filters as list.
+
+Markdown output is grouped by commit with subject lines. JSON output
+can be read back with git remarks import.
+
+Examples:
+  git remarks export --type doubt > doubts.md
+  git remarks export --format json --state all -o remarks.json
+  git remarks export --format csv --all-branches --range main..HEAD`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "md", "Output format: md, json, csv")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to file instead of stdout")
+	exportFilter.addFlags(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	var write func(io.Writer, []listEntry) error
+	switch exportFormat {
+	case "md", "markdown":
+		write = writeMarkdown
+	case "json":
+		write = writeJSON
+	case "csv":
+		write = writeCSV
+	default:
+		return fmt.Errorf("invalid format: %s (must be md, json, or csv)", exportFormat)
+	}
+
+	if err := exportFilter.resolve(); err != nil {
+		return err
+	}
+
+	s := store.New()
+	entries, err := collectRemarks(s, &exportFilter)
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", exportOutput, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := write(out, entries); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if exportOutput != "" {
+		fmt.Fprintf(os.Stderr, "✓ Exported %d remark%s to %s\n", len(entries), pluralize(len(entries)), exportOutput)
+	}
+	return nil
+}
+
+// writeMarkdown writes entries grouped by commit
+func writeMarkdown(w io.Writer, entries []listEntry) error {
+	fmt.Fprintf(w, "# Remarks: %s\n", exportFilter.scope())
+
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "\nNo remarks.\n")
+		return nil
+	}
+
+	lastCommit := ""
+	for _, e := range entries {
+		if e.Commit != lastCommit {
+			fmt.Fprintf(w, "\n## `%s` %s\n\n", e.ShortSHA, e.Subject)
+			lastCommit = e.Commit
+		}
+
+		meta := []string{e.Remark.CreatedAt.Format("2006-01-02")}
+		if exportFilter.AllBranches {
+			meta = append(meta, e.Remark.Branch)
+		}
+		if e.Remark.State != remark.StateActive {
+			meta = append(meta, string(e.Remark.State))
+		}
+		if e.Remark.Anchor != nil {
+			meta = append(meta, "`"+e.Remark.Anchor.String()+"`")
+		}
+
+		fmt.Fprintf(w, "- **%s** [%s] · %s\n", e.Remark.Type, e.Remark.ID, strings.Join(meta, " · "))
+		for _, line := range strings.Split(strings.TrimSpace(e.Remark.Body), "\n") {
+			if line == "" {
+				fmt.Fprintln(w)
+				continue
+			}
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+
+	return nil
+}
+
+// writeJSON writes entries in the format read by import
+func writeJSON(w io.Writer, entries []listEntry) error {
+	doc := remark.ExportDocument{
+		Version: remark.ExportVersion,
+		Remarks: make([]remark.Entry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		doc.Remarks = append(doc.Remarks, remark.Entry{Commit: e.Commit, Remark: e.Remark})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
+
+// writeCSV writes one row per remark
+func writeCSV(w io.Writer, entries []listEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(remark.CSVHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		entry := remark.Entry{Commit: e.Commit, Remark: e.Remark}
+		if err := cw.Write(entry.CSVRecord()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
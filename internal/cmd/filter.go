@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+// remarkFilter selects remarks by branch, type, state and commit range.
+// It is shared by the commands that list remarks.
+type remarkFilter struct {
+	Branch      string
+	AllBranches bool
+	Types       []string
+	State       string
+	Range       string
+}
+
+// addFlags registers the filter flags on a command
+func (f *remarkFilter) addFlags(c *cobra.Command) {
+	c.Flags().StringVar(&f.Branch, "branch", "", "Only remarks scoped to this branch (default: current branch)")
+	c.Flags().BoolVar(&f.AllBranches, "all-branches", false, "Include remarks from all branches")
+	c.Flags().StringSliceVar(&f.Types, "type", nil, "Only remarks of these types (comma-separated)")
+	c.Flags().StringVar(&f.State, "state", "active", "Only remarks in this state: active, resolved, all")
+	c.Flags().StringVar(&f.Range, "range", "", "Only remarks on commits in this revision range (default: HEAD)")
+}
+
+// resolve validates the filter and fills in the current branch when no
+// branch was given
+func (f *remarkFilter) resolve() error {
+	for _, t := range f.Types {
+		if !remark.ValidateType(t) {
+			return fmt.Errorf("invalid type: %s (must be thought, doubt, todo, or decision)", t)
+		}
+	}
+
+	switch f.State {
+	case "active", "resolved", "all":
+	default:
+		return fmt.Errorf("invalid state: %s (must be active, resolved, or all)", f.State)
+	}
+
+	if f.AllBranches || f.Branch != "" {
+		return nil
+	}
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		if errors.Is(err, git.ErrDetachedHead) {
+			return fmt.Errorf("not on a branch. Use --branch or --all-branches")
+		}
+		return fmt.Errorf("cannot determine current branch: %w", err)
+	}
+	f.Branch = branch
+	return nil
+}
+
+// scope describes the filter for output headers
+func (f *remarkFilter) scope() string {
+	if f.AllBranches {
+		return "all branches"
+	}
+	return f.Branch
+}
+
+// match returns true if a remark passes the branch, type and state filters
+func (f *remarkFilter) match(r remark.Remark) bool {
+	if !f.AllBranches && r.Branch != f.Branch {
+		return false
+	}
+
+	if f.State != "all" && string(r.State) != f.State {
+		return false
+	}
+
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if string(r.Type) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// collectRemarks returns the remarks matching the filter on commits in
+// its range, in history order (newest commit first)
+func collectRemarks(s *store.Store, f *remarkFilter) ([]listEntry, error) {
+	head, err := git.GetHEAD()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get HEAD: %w", err)
+	}
+
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	if len(allRemarks) == 0 {
+		return nil, nil
+	}
+
+	rev := head
+	if f.Range != "" {
+		// A leading dash would be read by git log as an option
+		if strings.HasPrefix(f.Range, "-") {
+			return nil, fmt.Errorf("invalid revision range: %s", f.Range)
+		}
+		rev = f.Range
+	}
+
+	history, err := git.GetHistory(rev, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision range %s: %w", rev, err)
+	}
+
+	var entries []listEntry
+	for pos, c := range history {
+		remarks, ok := allRemarks[c.SHA]
+		if !ok {
+			continue
+		}
+
+		for _, r := range remarks.Remarks {
+			if !f.match(r) {
+				continue
+			}
+			entries = append(entries, listEntry{
+				Commit:   c.SHA,
+				ShortSHA: c.ShortSHA,
+				Subject:  c.Subject,
+				Remark:   r,
+				IsHead:   c.SHA == head,
+				Position: pos,
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// describeState returns the wording used for remark counts
+func describeState(state string) string {
+	switch state {
+	case "active", "resolved":
+		return state + " "
+	default:
+		return ""
+	}
+}
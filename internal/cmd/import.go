@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	importFormat string
+	importBranch string
+	importDryRun bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import remarks from a JSON, YAML or CSV file",
+	Long: `Import remarks from a file, such as one written by git remarks export.
+
+Each entry needs a commit and a body. Abbreviated commit SHAs and refs
+are resolved. Missing IDs, types, branches, states and dates default
+to a new ID, thought, the current branch (or --branch), active and now.
+
+Entries whose ID already exists are skipped. All remarks are written in
+a single batch; if any entry is invalid, nothing is written.
+
+Examples:
+  git remarks import remarks.json
+  git remarks import notes.csv --branch feature/auth
+  git remarks import scratch.yaml --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "", "Input format: json, yaml, csv (default: from file extension)")
+	importCmd.Flags().StringVarP(&importBranch, "branch", "b", "", "Branch for entries without one (default: current branch)")
+	importCmd.Flags().BoolVarP(&importDryRun, "dry-run", "n", false, "Validate and report without writing")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	file := args[0]
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	format := importFormat
+	if format == "" {
+		format = formatFromExtension(file)
+	}
+
+	entries, err := parseImport(data, format)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	defaultBranch := importBranch
+	if defaultBranch == "" {
+		defaultBranch, _ = git.GetCurrentBranch()
+	}
+
+	s := store.New()
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	existing := make(map[string]bool)
+	for _, remarks := range allRemarks {
+		for _, r := range remarks.Remarks {
+			existing[r.ID] = true
+		}
+	}
+
+	batch := make(map[string]*remark.Remarks)
+	var invalid, skipped []string
+	imported := 0
+
+	for i, e := range entries {
+		label := fmt.Sprintf("entry %d", i+1)
+		if e.ID != "" {
+			label = fmt.Sprintf("entry %d [%s]", i+1, e.ID)
+		}
+
+		if err := normalizeEntry(&e, defaultBranch); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", label, err))
+			continue
+		}
+
+		if existing[e.ID] {
+			skipped = append(skipped, fmt.Sprintf("%s: already exists", label))
+			continue
+		}
+		existing[e.ID] = true
+
+		remarks, ok := batch[e.Commit]
+		if !ok {
+			if remarks, ok = allRemarks[e.Commit]; !ok {
+				remarks = &remark.Remarks{}
+			}
+			batch[e.Commit] = remarks
+		}
+		remarks.Add(e.Remark)
+		imported++
+	}
+
+	if len(invalid) > 0 {
+		for _, msg := range invalid {
+			fmt.Fprintf(os.Stderr, "  %s\n", msg)
+		}
+		return fmt.Errorf("%d invalid entr%s, nothing imported", len(invalid), pluralizeY(len(invalid)))
+	}
+
+	for _, msg := range skipped {
+		fmt.Printf("Skipped %s\n", msg)
+	}
+
+	if importDryRun {
+		fmt.Printf("Would import %d remark%s on %d commit%s\n", imported, pluralize(imported), len(batch), pluralize(len(batch)))
+		return nil
+	}
+
+	if imported == 0 {
+		fmt.Println("No new remarks to import")
+		return nil
+	}
+
+	if err := s.SaveBatch(batch, fmt.Sprintf("Imported remarks from %s", filepath.Base(file))); err != nil {
+		return fmt.Errorf("failed to import remarks: %w", err)
+	}
+
+	fmt.Printf("✓ Imported %d remark%s on %d commit%s", imported, pluralize(imported), len(batch), pluralize(len(batch)))
+	if len(skipped) > 0 {
+		fmt.Printf(" (%d skipped)", len(skipped))
+	}
+	fmt.Println()
+	return nil
+}
+
+// formatFromExtension guesses the input format from a file name
+func formatFromExtension(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return "csv"
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return "json"
+	}
+}
+
+// parseImport reads entries from an export document, a bare list of
+// entries, or CSV rows
+func parseImport(data []byte, format string) ([]remark.Entry, error) {
+	switch format {
+	case "json":
+		data = bytes.TrimSpace(data)
+		if len(data) > 0 && data[0] == '[' {
+			var entries []remark.Entry
+			err := json.Unmarshal(data, &entries)
+			return entries, err
+		}
+		var doc remark.ExportDocument
+		err := json.Unmarshal(data, &doc)
+		return doc.Remarks, err
+
+	case "yaml":
+		var entries []remark.Entry
+		if err := yaml.Unmarshal(data, &entries); err == nil {
+			return entries, nil
+		}
+		var doc remark.ExportDocument
+		err := yaml.Unmarshal(data, &doc)
+		return doc.Remarks, err
+
+	case "csv":
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+		entries := make([]remark.Entry, 0, len(records)-1)
+		for i, record := range records[1:] {
+			e, err := remark.EntryFromCSV(records[0], record)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", i+2, err)
+			}
+			entries = append(entries, e)
+		}
+		return entries, nil
+
+	default:
+		return nil, fmt.Errorf("unknown format: %s (must be json, yaml, or csv)", format)
+	}
+}
+
+// normalizeEntry validates an entry, resolves its commit to a full SHA
+// and fills in defaults for missing fields
+func normalizeEntry(e *remark.Entry, defaultBranch string) error {
+	if e.Commit == "" {
+		return fmt.Errorf("missing commit")
+	}
+	fullSHA, err := git.Run("rev-parse", "--verify", "--quiet", e.Commit+"^{commit}")
+	if err != nil || fullSHA == "" {
+		return fmt.Errorf("unknown commit: %s", e.Commit)
+	}
+	e.Commit = fullSHA
+
+	if e.Type == "" {
+		e.Type = remark.TypeThought
+	}
+	if !remark.ValidateType(string(e.Type)) {
+		return fmt.Errorf("invalid type: %s", e.Type)
+	}
+
+	switch e.State {
+	case "":
+		e.State = remark.StateActive
+	case remark.StateActive, remark.StateResolved:
+	default:
+		return fmt.Errorf("invalid state: %s", e.State)
+	}
+
+	if strings.TrimSpace(e.Body) == "" {
+		return fmt.Errorf("empty body")
+	}
+
+	if e.Branch == "" {
+		if defaultBranch == "" {
+			return fmt.Errorf("missing branch (use --branch)")
+		}
+		e.Branch = defaultBranch
+	}
+
+	if e.ID == "" {
+		e.ID = remark.NewID()
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+
+	return nil
+}
+
+func pluralizeY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	importNotesFrom   string
+	importNotesType   string
+	importNotesBranch string
+	importNotesRemove bool
+	importNotesDryRun bool
+)
+
+var importNotesCmd = &cobra.Command{
+	Use:   "import-notes --from <ref>",
+	Short: "Convert notes from another notes ref into remarks",
+	Long: `Convert plain git notes (or notes from other tools) into remarks.
+
+Each note on a commit becomes a remark with the given type and branch.
+The author and date of the notes commit that added the note are kept.
+Notes that are already remark documents are imported as they are.
+With --remove, the imported notes are removed from the source ref; a
+note with remarks skipped because their ID already exists is kept.
+
+Examples:
+  git remarks import-notes --from commits
+  git remarks import-notes --from refs/notes/review --type doubt --branch main
+  git remarks import-notes --from commits --remove`,
+	Args: cobra.NoArgs,
+	RunE: runImportNotes,
+}
+
+func init() {
+	importNotesCmd.Flags().StringVar(&importNotesFrom, "from", "commits", "Notes ref to import from")
+	importNotesCmd.Flags().StringVarP(&importNotesType, "type", "t", "thought", "Remark type for imported notes")
+	importNotesCmd.Flags().StringVarP(&importNotesBranch, "branch", "b", "", "Branch for imported notes (default: current branch)")
+	importNotesCmd.Flags().BoolVar(&importNotesRemove, "remove", false, "Remove the source notes after importing")
+	importNotesCmd.Flags().BoolVarP(&importNotesDryRun, "dry-run", "n", false, "Report what would be imported without writing")
+}
+
+func runImportNotes(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	if !remark.ValidateType(importNotesType) {
+		return fmt.Errorf("invalid type: %s (must be thought, doubt, todo, or decision)", importNotesType)
+	}
+
+	branch := importNotesBranch
+	if branch == "" {
+		var err error
+		branch, err = git.GetCurrentBranch()
+		if err != nil {
+			return fmt.Errorf("not on a branch. Use --branch to specify one")
+		}
+	}
+
+	s := store.New()
+	src := store.NewWithRef(importNotesFrom)
+	if src.Ref() == s.Ref() {
+		return fmt.Errorf("cannot import notes from %s into itself", src.Ref())
+	}
+
+	objects, err := src.ListAnnotated()
+	if err != nil {
+		return fmt.Errorf("failed to list notes in %s: %w", src.Ref(), err)
+	}
+	if len(objects) == 0 {
+		fmt.Printf("No notes found in %s\n", src.Ref())
+		return nil
+	}
+
+	types, err := git.GetObjectTypes(objects)
+	if err != nil {
+		return fmt.Errorf("failed to inspect annotated objects: %w", err)
+	}
+
+	origins, err := git.GetNoteOrigins(src.Ref())
+	if err != nil {
+		return fmt.Errorf("failed to read history of %s: %w", src.Ref(), err)
+	}
+
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	existing := make(map[string]bool)
+	for _, remarks := range allRemarks {
+		for _, r := range remarks.Remarks {
+			existing[r.ID] = true
+		}
+	}
+
+	batch := make(map[string]*remark.Remarks)
+	var converted []string
+	imported, skipped, kept := 0, 0, 0
+
+	for _, object := range objects {
+		if types[object] != "commit" {
+			fmt.Printf("Skipped note on %s %s\n", types[object], shortSHA(object))
+			skipped++
+			continue
+		}
+
+		raw, err := src.GetRaw(object)
+		if err != nil {
+			return fmt.Errorf("failed to read note on %s: %w", shortSHA(object), err)
+		}
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		remarks := noteToRemarks(raw, branch, origins[object])
+
+		target, ok := batch[object]
+		if !ok {
+			if target, ok = allRemarks[object]; !ok {
+				target = &remark.Remarks{}
+			}
+		}
+
+		added := 0
+		for _, r := range remarks {
+			if existing[r.ID] {
+				fmt.Printf("Skipped [%s] on %s: already exists\n", r.ID, shortSHA(object))
+				skipped++
+				continue
+			}
+			existing[r.ID] = true
+			target.Add(r)
+			added++
+		}
+
+		if added > 0 {
+			batch[object] = target
+			imported += added
+		}
+		// A note with skipped remarks is kept so --remove loses nothing
+		if added == len(remarks) {
+			converted = append(converted, object)
+		} else {
+			kept++
+		}
+	}
+
+	if importNotesDryRun {
+		fmt.Printf("Would import %d remark%s from %s\n", imported, pluralize(imported), src.Ref())
+		return nil
+	}
+
+	if imported > 0 {
+		if err := s.SaveBatch(batch, "Imported notes from "+src.Ref()); err != nil {
+			return fmt.Errorf("failed to import notes: %w", err)
+		}
+	}
+
+	fmt.Printf("✓ Imported %d remark%s from %s", imported, pluralize(imported), src.Ref())
+	if skipped > 0 {
+		fmt.Printf(" (%d skipped)", skipped)
+	}
+	fmt.Println()
+
+	if importNotesRemove && len(converted) > 0 {
+		removeArgs := append([]string{"notes", "--ref=" + src.Ref(), "remove", "--ignore-missing"}, converted...)
+		if _, err := git.Run(removeArgs...); err != nil {
+			return fmt.Errorf("failed to remove source notes: %w", err)
+		}
+		fmt.Printf("✓ Removed %d note%s from %s\n", len(converted), pluralize(len(converted)), src.Ref())
+	}
+	if importNotesRemove && kept > 0 {
+		fmt.Printf("Kept %d note%s with skipped remarks in %s\n", kept, pluralize(kept), src.Ref())
+	}
+
+	return nil
+}
+
+// noteToRemarks converts a note into remarks. Notes that are remark
+// documents keep their remarks; any other note becomes one remark.
+func noteToRemarks(raw, branch string, origin git.NoteOrigin) []remark.Remark {
+	if parsed, err := remark.ParseRemarks([]byte(raw)); err == nil && !parsed.IsEmpty() && parsed.Remarks[0].ID != "" {
+		return parsed.Remarks
+	}
+
+	r := remark.NewRemark(remark.Type(importNotesType), branch, strings.TrimSpace(raw))
+	r.Author = origin.Author
+	if !origin.Date.IsZero() {
+		r.CreatedAt = origin.Date.UTC()
+	}
+	return []remark.Remark{r}
+}
@@ -7,8 +7,35 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/hook"
 )
 
+var (
+	initUpgrade   bool
+	initGlobal    bool
+	initManager   string
+	initPrePush   bool
+	initCommitMsg bool
+)
+
+// managedHook is a hook script that init installs
+type managedHook struct {
+	Name   string
+	Script string
+	// Flag is the init flag that installs the hook, if it is optional
+	Flag string
+}
+
+var (
+	postRewriteHook = managedHook{Name: "post-rewrite", Script: hook.PostRewrite}
+	prePushHook     = managedHook{Name: "pre-push", Script: hook.PrePush, Flag: "--pre-push"}
+	commitMsgHook   = managedHook{Name: "commit-msg", Script: hook.CommitMsg, Flag: "--commit-msg"}
+	postCommitHook  = managedHook{Name: "post-commit", Script: hook.PostCommit, Flag: "--commit-msg"}
+)
+
+// managedHooks lists every hook init can install, for uninit
+var managedHooks = []managedHook{postRewriteHook, prePushHook, commitMsgHook, postCommitHook}
+
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Install git-remarks hooks in the current repository",
@@ -17,102 +44,237 @@ var initCmd = &cobra.Command{
 This hook ensures that remarks survive rebases and amends by
 migrating them to the new commit SHAs.
 
+If a post-rewrite hook already exists, a block delimited by
+"# >>> git-remarks >>>" and "# <<< git-remarks <<<" is appended to it.
+Use --upgrade to replace an outdated block, and 'git remarks uninit'
+to remove it. Content outside the block is never changed.
+
+The hook is installed where git runs hooks from: core.hooksPath if set,
+otherwise the hooks directory shared by all worktrees.
+
+With --global, the hook is installed into the global core.hooksPath if
+set, or into the init.templateDir template (created as ~/.git-templates
+if unset), so every new clone and 'git init' gets it automatically.
+
+With --pre-push, a pre-push hook is installed as well. It lists the
+active todo and doubt remarks on the commits being pushed, and refuses
+the push if git config remarks.prePush is set to block (warn is the
+default, ignore turns it off). 'git push --no-verify' skips it. An
+existing pre-push hook is left unchanged, since its commands may read
+the pushed refs from stdin before git-remarks could.
+
+With --commit-msg, commit-msg and post-commit hooks are installed as
+well. Lines such as "Remark(todo): tune the backoff" are then removed
+from commit messages and added as remarks on the new commit. Merge
+commits keep them. Remarks that cannot be attached to their commit are
+saved in .git/REMARKS_UNATTACHED.
+
+With --manager, the migrate-rewrites step is added to the config of a
+hook manager instead (lefthook.yml, .pre-commit-config.yaml or
+.husky/post-rewrite), keeping its existing contents. Use --manager auto
+to detect the manager.
+
 Examples:
-  git remarks init`,
+  git remarks init
+  git remarks init --upgrade
+  git remarks init --global
+  git remarks init --pre-push
+  git remarks init --commit-msg
+  git remarks init --manager lefthook`,
 	RunE: runInit,
 }
 
-const postRewriteHook = `#!/bin/sh
-# git-remarks post-rewrite hook
-# Migrates remarks when commits are rewritten (rebase, amend)
-
-rewrite_type="$1"
-
-# Check if git-remarks is available
-if ! command -v git-remarks >/dev/null 2>&1; then
-    exit 0
-fi
+func init() {
+	initCmd.Flags().BoolVar(&initUpgrade, "upgrade", false, "Replace an outdated git-remarks hook block")
+	initCmd.Flags().BoolVar(&initGlobal, "global", false, "Install for all repositories (global hooks path or init template)")
+	initCmd.Flags().StringVar(&initManager, "manager", "", "Add the step to a hook manager: lefthook, pre-commit, husky, auto")
+	initCmd.Flags().BoolVar(&initPrePush, "pre-push", false, "Also install a pre-push hook that checks for unresolved remarks")
+	initCmd.Flags().BoolVar(&initCommitMsg, "commit-msg", false, "Also install hooks that turn Remark(type): lines in commit messages into remarks")
+}
 
-# Pass stdin to git-remarks migrate-rewrites
-git-remarks migrate-rewrites "$rewrite_type"
-`
+// initHooks returns the hooks selected by the init flags
+func initHooks() []managedHook {
+	hooks := []managedHook{postRewriteHook}
+	if initPrePush {
+		hooks = append(hooks, prePushHook)
+	}
+	if initCommitMsg {
+		hooks = append(hooks, commitMsgHook, postCommitHook)
+	}
+	return hooks
+}
 
 func runInit(cmd *cobra.Command, args []string) error {
+	if initGlobal {
+		return runInitGlobal()
+	}
+
 	if !git.IsInsideWorkTree() {
 		return fmt.Errorf("not a git repository")
 	}
 
-	gitDir, err := git.GetGitDir()
+	hooksDir, err := git.GetHooksDir()
 	if err != nil {
-		return fmt.Errorf("failed to get git directory: %w", err)
+		return fmt.Errorf("failed to get hooks directory: %w", err)
 	}
 
-	hooksDir := filepath.Join(gitDir, "hooks")
-	hookPath := filepath.Join(hooksDir, "post-rewrite")
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get repository root: %w", err)
+	}
 
-	// Check if hooks directory exists
-	if err := os.MkdirAll(hooksDir, 0755); err != nil {
-		return fmt.Errorf("failed to create hooks directory: %w", err)
+	if initManager != "" {
+		if initPrePush || initCommitMsg {
+			return fmt.Errorf("--pre-push and --commit-msg cannot be combined with --manager")
+		}
+		return runInitManager(root, hooksDir)
 	}
 
-	// Check if hook already exists
-	if _, err := os.Stat(hookPath); err == nil {
-		// Hook exists - check if it's ours
-		content, err := os.ReadFile(hookPath)
+	for _, h := range initHooks() {
+		message, err := installHook(hooksDir, h, initUpgrade)
 		if err != nil {
-			return fmt.Errorf("failed to read existing hook: %w", err)
+			return err
 		}
+		fmt.Println(message)
+	}
 
-		if containsRemarksHook(string(content)) {
-			fmt.Println("✓ git-remarks hook already installed")
-			return nil
-		}
+	if m, ok := hook.DetectManager(root); ok {
+		fmt.Printf("  %s is set up here and may overwrite this hook. Use 'git remarks init --manager %s' instead\n", m, m)
+	}
+	return nil
+}
 
-		// Append to existing hook
-		f, err := os.OpenFile(hookPath, os.O_APPEND|os.O_WRONLY, 0755)
-		if err != nil {
-			return fmt.Errorf("failed to open existing hook: %w", err)
+func runInitManager(root, hooksDir string) error {
+	var m hook.Manager
+	if initManager == "auto" {
+		var ok bool
+		m, ok = hook.DetectManager(root)
+		if !ok {
+			return fmt.Errorf("no hook manager found (looked for lefthook, pre-commit and husky config)")
 		}
-		defer f.Close()
-
-		appendHook := `
-# git-remarks hook (appended)
-if command -v git-remarks >/dev/null 2>&1; then
-    git-remarks migrate-rewrites "$1"
-fi
-`
-		if _, err := f.WriteString(appendHook); err != nil {
-			return fmt.Errorf("failed to append to hook: %w", err)
+	} else {
+		var err error
+		m, err = hook.ParseManager(initManager)
+		if err != nil {
+			return err
 		}
+	}
 
-		fmt.Println("✓ git-remarks hook appended to existing post-rewrite hook")
-		return nil
+	result, err := hook.InstallManager(root, m, initUpgrade)
+	if err != nil {
+		return err
+	}
+
+	config := hook.ConfigPath(root, m)
+	if rel, err := filepath.Rel(root, config); err == nil {
+		config = rel
 	}
 
-	// Create new hook
-	if err := os.WriteFile(hookPath, []byte(postRewriteHook), 0755); err != nil {
-		return fmt.Errorf("failed to write hook: %w", err)
+	switch result {
+	case hook.Installed, hook.Appended:
+		fmt.Printf("✓ Added git-remarks step to %s\n", config)
+	case hook.Upgraded:
+		fmt.Printf("✓ Upgraded git-remarks step in %s\n", config)
+	case hook.NeedsUpgrade:
+		fmt.Printf("git-remarks step in %s is outdated. Run 'git remarks init --manager %s --upgrade' to replace it\n", config, m)
+		return nil
+	case hook.Manual:
+		fmt.Printf("%s already calls git-remarks outside a managed block; leaving it unchanged\n", config)
+		return nil
+	default:
+		fmt.Printf("✓ git-remarks step already in %s\n", config)
 	}
 
-	fmt.Println("✓ git-remarks hook installed")
+	if !hook.RunsHooks(root, hooksDir, m) {
+		fmt.Printf("  Run '%s' so %s installs the post-rewrite hook\n", hook.InstallCommand(m), m)
+	}
 	return nil
 }
 
-func containsRemarksHook(content string) bool {
-	return len(content) > 0 && 
-		(contains(content, "git-remarks") || contains(content, "git remarks"))
-}
+func runInitGlobal() error {
+	hooksDir, isHooksPath, err := globalHooksDir(true)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range initHooks() {
+		message, err := installHook(hooksDir, h, initUpgrade)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s in %s\n", message, hooksDir)
+	}
 
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && searchString(s, substr)
+	if isHooksPath {
+		fmt.Println("  Global core.hooksPath is set, so every repository runs these hooks")
+	} else {
+		fmt.Println("  New clones get the hooks; run 'git init' in an existing repository to copy them there")
+	}
+	return nil
 }
 
-func searchString(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+// globalHooksDir returns the global core.hooksPath if set, or the hooks
+// directory of the init.templateDir template. With create, an unset
+// template directory is configured as ~/.git-templates.
+func globalHooksDir(create bool) (string, bool, error) {
+	if hooksPath, _ := git.Run("config", "--global", "--path", "core.hooksPath"); hooksPath != "" {
+		return hooksPath, true, nil
+	}
+
+	templateDir, _ := git.Run("config", "--global", "--path", "init.templateDir")
+	if templateDir == "" {
+		if !create {
+			return "", false, fmt.Errorf("no global core.hooksPath or init.templateDir configured")
+		}
+
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false, fmt.Errorf("cannot determine home directory: %w", err)
+		}
+		templateDir = filepath.Join(home, ".git-templates")
+		if _, err := git.Run("config", "--global", "init.templateDir", templateDir); err != nil {
+			return "", false, fmt.Errorf("failed to set init.templateDir: %w", err)
 		}
+		fmt.Printf("✓ Set init.templateDir to %s\n", templateDir)
+	}
+
+	return filepath.Join(templateDir, "hooks"), false, nil
+}
+
+// installHook installs a hook in hooksDir and returns a status message
+func installHook(hooksDir string, h managedHook, upgrade bool) (string, error) {
+	result, err := hook.Install(filepath.Join(hooksDir, h.Name), h.Script, upgrade)
+	if err != nil {
+		return "", err
+	}
+
+	switch result {
+	case hook.Installed:
+		return fmt.Sprintf("✓ git-remarks %s hook installed", h.Name), nil
+	case hook.Appended:
+		return fmt.Sprintf("✓ git-remarks hook appended to existing %s hook", h.Name), nil
+	case hook.Upgraded:
+		return fmt.Sprintf("✓ git-remarks %s hook upgraded", h.Name), nil
+	case hook.NeedsUpgrade:
+		return fmt.Sprintf("git-remarks %s hook is outdated. Run '%s' to replace it", h.Name, initCommand(h, true)), nil
+	case hook.Manual:
+		return fmt.Sprintf("%s hook already calls git-remarks outside a managed block; leaving it unchanged", h.Name), nil
+	case hook.NotAppended:
+		return fmt.Sprintf("%s hook already exists and may read stdin before git-remarks could; leaving it unchanged.\n"+
+			"  Add 'git-remarks %s \"$1\"' to it by hand, passing it the same stdin as the hook's other commands", h.Name, h.Name), nil
+	default:
+		return fmt.Sprintf("✓ git-remarks %s hook already installed", h.Name), nil
 	}
-	return false
 }
 
+// initCommand returns the init command line that installs a hook
+func initCommand(h managedHook, upgrade bool) string {
+	command := "git remarks init"
+	if h.Flag != "" {
+		command += " " + h.Flag
+	}
+	if upgrade {
+		command += " --upgrade"
+	}
+	return command
+}
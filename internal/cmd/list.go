@@ -2,133 +2,274 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/format"
 	"github.com/Enigama/git-remarks/internal/git"
 	"github.com/Enigama/git-remarks/internal/remark"
 	"github.com/Enigama/git-remarks/internal/store"
 )
 
+var (
+	listSort    string
+	listReverse bool
+	listGroupBy string
+	listFilter  remarkFilter
+)
+
 var listCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [-- <path>...]",
 	Short: "List active remarks on the current branch",
 	Long: `List all active remarks that are relevant to the current branch.
 
 This scans the commit history from HEAD and shows all active remarks
-that are scoped to the current branch.`,
+that are scoped to the current branch. Remarks are shown in history
+order, newest commit first.
+
+When paths are given, only remarks anchored to those files (or to files
+inside those directories) are shown.
+
+Examples:
+  git remarks list
+  git remarks list --sort created --reverse
+  git remarks list --group-by commit
+  git remarks list --type todo,doubt --state all
+  git remarks list --all-branches --range main..HEAD
+  git remarks list -- internal/auth`,
 	RunE: runList,
 }
 
+func init() {
+	// The root command falls back to list, so it accepts the same flags
+	for _, c := range []*cobra.Command{listCmd, rootCmd} {
+		c.Flags().StringVar(&listSort, "sort", "commit", "Sort order: commit, created, type")
+		c.Flags().BoolVar(&listReverse, "reverse", false, "Reverse the sort order")
+		c.Flags().StringVar(&listGroupBy, "group-by", "", "Group output: commit, type")
+		listFilter.addFlags(c)
+	}
+}
+
+// listEntry is a remark together with the commit it is attached to
+type listEntry struct {
+	Commit   string
+	ShortSHA string
+	Subject  string
+	Remark   remark.Remark
+	IsHead   bool
+	Position int // position in history (0 = HEAD)
+}
+
 func runList(cmd *cobra.Command, args []string) error {
 	if !git.IsInsideWorkTree() {
 		return fmt.Errorf("not a git repository")
 	}
 
-	branch, err := git.GetCurrentBranch()
-	if err != nil {
-		return fmt.Errorf("cannot determine current branch: %w", err)
+	if err := validateListOptions(listSort, listGroupBy); err != nil {
+		return err
 	}
 
-	head, err := git.GetHEAD()
-	if err != nil {
-		return fmt.Errorf("cannot get HEAD: %w", err)
+	if err := listFilter.resolve(); err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		p, err := git.RepoPath(arg)
+		if err != nil {
+			return fmt.Errorf("invalid path %s: %w", arg, err)
+		}
+		paths = append(paths, p)
 	}
 
 	s := store.New()
-	allRemarks, err := s.ListAllWithRemarks()
+	entries, err := collectRemarks(s, &listFilter)
 	if err != nil {
-		return fmt.Errorf("failed to list remarks: %w", err)
+		return err
+	}
+
+	if len(paths) > 0 {
+		entries = filterByPaths(entries, paths)
+	}
+
+	state := describeState(listFilter.State)
+	if len(entries) == 0 {
+		fmt.Printf("%s (no %sremarks)\n", listFilter.scope(), state)
+		return nil
 	}
 
-	// Collect active remarks for current branch that are ancestors of HEAD
-	type remarkWithCommit struct {
-		Commit    string
-		ShortSHA  string
-		Remark    remark.Remark
-		IsHead    bool
-		Ancestors int // position in history (0 = HEAD)
+	sortEntries(entries, listSort, listReverse)
+
+	fmt.Printf("%s (%d %sremark%s)\n\n", listFilter.scope(), len(entries), state, pluralize(len(entries)))
+
+	switch listGroupBy {
+	case "commit":
+		printGroupedByCommit(entries)
+	case "type":
+		printGroupedByType(entries)
+	default:
+		for _, e := range entries {
+			fmt.Printf("[%s] %s · %s · %s%s%s%s\n", e.Remark.ID, e.Remark.Type, formatAge(e.Remark.CreatedAt), e.ShortSHA, headSuffix(e.IsHead), sharedSuffix(e.Remark), anchorSuffix(e.Remark.Anchor))
+			printBody(e.Remark.Body)
+		}
 	}
 
-	var activeRemarks []remarkWithCommit
+	return nil
+}
+
+// validateListOptions checks the --sort and --group-by values
+func validateListOptions(sortBy, groupBy string) error {
+	switch sortBy {
+	case "commit", "created", "type":
+	default:
+		return fmt.Errorf("invalid sort: %s (must be commit, created, or type)", sortBy)
+	}
+
+	switch groupBy {
+	case "", "commit", "type":
+	default:
+		return fmt.Errorf("invalid group-by: %s (must be commit or type)", groupBy)
+	}
+
+	return nil
+}
 
-	for commit, remarks := range allRemarks {
-		// Check if this commit is an ancestor of HEAD (or is HEAD)
-		isAncestor := commit == head
-		if !isAncestor {
-			isAnc, err := git.IsAncestor(commit, head)
-			if err != nil {
-				continue
+// sortEntries orders entries by commit (history order, newest first),
+// created (newest first) or type
+func sortEntries(entries []listEntry, by string, reverse bool) {
+	less := func(a, b listEntry) bool {
+		switch by {
+		case "created":
+			if !a.Remark.CreatedAt.Equal(b.Remark.CreatedAt) {
+				return a.Remark.CreatedAt.After(b.Remark.CreatedAt)
 			}
-			isAncestor = isAnc
+		case "type":
+			if ra, rb := typeRank(a.Remark.Type), typeRank(b.Remark.Type); ra != rb {
+				return ra < rb
+			}
+		}
+		if a.Position != b.Position {
+			return a.Position < b.Position
 		}
+		return a.Remark.CreatedAt.Before(b.Remark.CreatedAt)
+	}
 
-		if !isAncestor {
-			continue
+	sort.SliceStable(entries, func(i, j int) bool {
+		if reverse {
+			return less(entries[j], entries[i])
 		}
+		return less(entries[i], entries[j])
+	})
+}
 
-		active := remarks.ActiveForBranch(branch)
-		for _, r := range active {
-			shortSHA, _ := git.GetShortSHA(commit)
-			activeRemarks = append(activeRemarks, remarkWithCommit{
-				Commit:   commit,
-				ShortSHA: shortSHA,
-				Remark:   r,
-				IsHead:   commit == head,
-			})
+// typeRank returns the display position of a remark type
+func typeRank(t remark.Type) int {
+	for i, known := range remark.Types {
+		if known == t {
+			return i
 		}
 	}
+	return len(remark.Types)
+}
 
-	if len(activeRemarks) == 0 {
-		fmt.Printf("%s (no active remarks)\n", branch)
-		return nil
+// printGroupedByCommit prints sorted entries under a header per commit
+func printGroupedByCommit(entries []listEntry) {
+	var groups [][]listEntry
+	index := make(map[string]int)
+	for _, e := range entries {
+		i, ok := index[e.Commit]
+		if !ok {
+			i = len(groups)
+			index[e.Commit] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], e)
 	}
 
-	fmt.Printf("%s (%d active remark%s)\n\n", branch, len(activeRemarks), pluralize(len(activeRemarks)))
+	for _, group := range groups {
+		first := group[0]
+		fmt.Printf("── %s%s %s\n\n", first.ShortSHA, headSuffix(first.IsHead), first.Subject)
+		for _, e := range group {
+			fmt.Printf("[%s] %s · %s%s%s\n", e.Remark.ID, e.Remark.Type, formatAge(e.Remark.CreatedAt), sharedSuffix(e.Remark), anchorSuffix(e.Remark.Anchor))
+			printBody(e.Remark.Body)
+		}
+	}
+}
 
-	for _, r := range activeRemarks {
-		headIndicator := ""
-		if r.IsHead {
-			headIndicator = " (HEAD)"
+// printGroupedByType prints sorted entries under a header per remark type
+func printGroupedByType(entries []listEntry) {
+	var order []remark.Type
+	groups := make(map[remark.Type][]listEntry)
+	for _, e := range entries {
+		if _, ok := groups[e.Remark.Type]; !ok {
+			order = append(order, e.Remark.Type)
 		}
+		groups[e.Remark.Type] = append(groups[e.Remark.Type], e)
+	}
 
-		age := formatAge(r.Remark.CreatedAt)
-		fmt.Printf("[%s] %s · %s · %s%s\n", r.Remark.ID, r.Remark.Type, age, r.ShortSHA, headIndicator)
-		
-		// Indent the body
-		lines := strings.Split(strings.TrimSpace(r.Remark.Body), "\n")
-		for _, line := range lines {
-			fmt.Printf("  %s\n", line)
+	for _, t := range order {
+		group := groups[t]
+		fmt.Printf("── %s (%d)\n\n", t, len(group))
+		for _, e := range group {
+			fmt.Printf("[%s] %s · %s%s %s%s%s\n", e.Remark.ID, formatAge(e.Remark.CreatedAt), e.ShortSHA, headSuffix(e.IsHead), e.Subject, sharedSuffix(e.Remark), anchorSuffix(e.Remark.Anchor))
+			printBody(e.Remark.Body)
 		}
-		fmt.Println()
 	}
+}
 
-	return nil
+// filterByPaths keeps entries whose anchor matches one of the paths
+func filterByPaths(entries []listEntry, paths []string) []listEntry {
+	var result []listEntry
+	for _, e := range entries {
+		if e.Remark.Anchor == nil {
+			continue
+		}
+		for _, p := range paths {
+			if e.Remark.Anchor.MatchesPath(p) {
+				result = append(result, e)
+				break
+			}
+		}
+	}
+	return result
 }
 
-func pluralize(n int) string {
-	if n == 1 {
+func sharedSuffix(r remark.Remark) string {
+	if !r.IsShared() {
 		return ""
 	}
-	return "s"
+	return " · shared"
 }
 
-func formatAge(t time.Time) string {
-	d := time.Since(t)
-
-	switch {
-	case d < time.Minute:
-		return "just now"
-	case d < time.Hour:
-		mins := int(d.Minutes())
-		return fmt.Sprintf("%dm ago", mins)
-	case d < 24*time.Hour:
-		hours := int(d.Hours())
-		return fmt.Sprintf("%dh ago", hours)
-	default:
-		days := int(d.Hours() / 24)
-		return fmt.Sprintf("%dd ago", days)
+func anchorSuffix(anchor *remark.Anchor) string {
+	if anchor == nil {
+		return ""
+	}
+	return " · " + anchor.String()
+}
+
+func headSuffix(isHead bool) string {
+	if isHead {
+		return " (HEAD)"
 	}
+	return ""
 }
 
+// printBody prints an indented remark body followed by a blank line
+func printBody(body string) {
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	for _, line := range lines {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+}
+
+func pluralize(n int) string {
+	return format.Plural(n)
+}
+
+func formatAge(t time.Time) string {
+	return format.Age(t)
+}
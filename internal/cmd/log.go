@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	logMaxCount        int
+	logOnlyWithRemarks bool
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log [revision-range]",
+	Short: "Show commit history interleaved with remarks",
+	Long: `Show commit history with the remarks on each commit.
+
+If no revision range is specified, shows commits from the merge-base
+with main (or master) to HEAD. On the main branch itself, the whole
+history of HEAD is shown.
+
+Examples:
+  git remarks log
+  git remarks log main..feature/auth
+  git remarks log -n 20 HEAD
+  git remarks log --only-with-remarks`,
+	RunE: runLog,
+}
+
+func init() {
+	logCmd.Flags().IntVarP(&logMaxCount, "max-count", "n", 0, "Limit the number of commits shown")
+	logCmd.Flags().BoolVar(&logOnlyWithRemarks, "only-with-remarks", false, "Only show commits that have remarks")
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	revs := args
+	for _, rev := range revs {
+		// A leading dash would be read by git log as an option
+		if strings.HasPrefix(rev, "-") {
+			return fmt.Errorf("invalid revision range: %s", rev)
+		}
+	}
+	if len(revs) == 0 {
+		var err error
+		revs, err = defaultLogRange()
+		if err != nil {
+			return err
+		}
+	}
+
+	// With --only-with-remarks the limit applies to the commits shown,
+	// so the whole range has to be walked first
+	gitLimit := logMaxCount
+	if logOnlyWithRemarks {
+		gitLimit = 0
+	}
+
+	commits, err := git.GetLog(revs, gitLimit)
+	if err != nil {
+		return fmt.Errorf("invalid revision range %s: %w", strings.Join(revs, " "), err)
+	}
+
+	// Remarks are scoped to the current branch; in detached HEAD all
+	// active remarks are shown
+	branch, err := git.GetCurrentBranch()
+	if err != nil && !errors.Is(err, git.ErrDetachedHead) {
+		return fmt.Errorf("cannot determine current branch: %w", err)
+	}
+
+	s := store.New()
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	shown := 0
+	for _, c := range commits {
+		if logOnlyWithRemarks && logMaxCount > 0 && shown >= logMaxCount {
+			break
+		}
+
+		var active []remark.Remark
+		if remarks, ok := allRemarks[c.SHA]; ok {
+			active = activeRemarks(remarks, branch)
+		}
+
+		if logOnlyWithRemarks && len(active) == 0 {
+			continue
+		}
+		shown++
+
+		fmt.Printf("%s %s\n", colorize(colorYellow, c.ShortSHA), c.Subject)
+		for _, r := range active {
+			label := fmt.Sprintf("[%s] %s", r.ID, r.Type)
+			fmt.Printf("    %s · %s\n", colorize(typeColor(r.Type), label), formatAge(r.CreatedAt))
+			for _, line := range strings.Split(strings.TrimSpace(r.Body), "\n") {
+				fmt.Printf("      %s\n", line)
+			}
+		}
+		if len(active) > 0 {
+			fmt.Println()
+		}
+	}
+
+	if shown == 0 {
+		fmt.Println("No commits to show")
+	}
+
+	return nil
+}
+
+// defaultLogRange returns the range from the merge-base with the main
+// branch to HEAD, or all of HEAD when HEAD is the merge-base
+func defaultLogRange() ([]string, error) {
+	head, err := git.GetHEAD()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get HEAD: %w", err)
+	}
+
+	base, err := git.GetDefaultBase()
+	if err != nil {
+		if errors.Is(err, git.ErrNoBaseBranch) {
+			return []string{head}, nil
+		}
+		return nil, fmt.Errorf("cannot determine merge-base: %w", err)
+	}
+
+	if base == head {
+		return []string{head}, nil
+	}
+	return []string{base + ".." + head}, nil
+}
+
+// activeRemarks returns the active remarks for a branch, or all active
+// remarks when branch is empty
+func activeRemarks(remarks *remark.Remarks, branch string) []remark.Remark {
+	if branch != "" {
+		return remarks.ActiveForBranch(branch)
+	}
+
+	var result []remark.Remark
+	for _, r := range remarks.Remarks {
+		if r.State == remark.StateActive {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	mergeDriverPreferLatest bool
+	mergeDriverNoCommit     bool
+)
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver",
+	Short: "Resolve conflicts from 'git notes merge' by remark ID",
+	Long: `Resolve the conflicts left in NOTES_MERGE_WORKTREE by
+'git notes --ref=remarks merge'.
+
+Each conflicting note is merged from the notes merge-base, the local
+and the remote version, matching remarks by ID: remarks added on either
+side are kept, fields changed on one side take that side's value, and
+resolved remarks stay resolved. When all notes merge cleanly, the
+notes merge is committed.
+
+A remark field changed on both sides is a true conflict: the note is
+left as it is for manual resolution, unless --prefer-latest is given,
+which keeps the most recently modified side.
+
+When the merge is into refs/notes/remarks, the shared remarks of the
+merged notes are mirrored to refs/notes/remarks-shared once it is
+committed. When it is into refs/notes/remarks-shared, shared remarks it
+removes are removed from refs/notes/remarks too.
+
+To avoid conflicts altogether, set notes.remarks-shared.mergeStrategy
+to union; git-remarks reads the concatenated notes it produces by
+remark ID.
+
+Examples:
+  git notes --ref=remarks-shared merge refs/notes/remotes/origin/remarks-shared
+  git remarks merge-driver`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runMergeDriver,
+}
+
+func init() {
+	mergeDriverCmd.Flags().BoolVar(&mergeDriverPreferLatest, "prefer-latest", false, "Resolve true conflicts with the most recently modified side")
+	mergeDriverCmd.Flags().BoolVar(&mergeDriverNoCommit, "no-commit", false, "Resolve the notes but do not commit the merge")
+}
+
+func runMergeDriver(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	worktree, err := git.Run("rev-parse", "--git-path", "NOTES_MERGE_WORKTREE")
+	if err != nil {
+		return fmt.Errorf("failed to get git directory: %w", err)
+	}
+	entries, err := os.ReadDir(worktree)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no notes merge in progress")
+		}
+		return fmt.Errorf("failed to read %s: %w", worktree, err)
+	}
+
+	mergeRef, err := notesMergeRef()
+	if err != nil {
+		return err
+	}
+
+	ours, err := git.Run("rev-parse", "NOTES_MERGE_PARTIAL^1")
+	if err != nil {
+		return fmt.Errorf("failed to read notes merge state: %w", err)
+	}
+	theirs, err := git.Run("rev-parse", "NOTES_MERGE_PARTIAL^2")
+	if err != nil {
+		return fmt.Errorf("failed to read notes merge state: %w", err)
+	}
+
+	base := remark.Notes{}
+	if mergeBase, err := git.Run("merge-base", ours, theirs); err == nil && mergeBase != "" {
+		if base, err = store.ListAt(mergeBase); err != nil {
+			return fmt.Errorf("failed to read merge-base remarks: %w", err)
+		}
+	}
+	ourNotes, err := store.ListAt(ours)
+	if err != nil {
+		return fmt.Errorf("failed to read local remarks: %w", err)
+	}
+	theirNotes, err := store.ListAt(theirs)
+	if err != nil {
+		return fmt.Errorf("failed to read remote remarks: %w", err)
+	}
+
+	resolved, unresolved := 0, 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		object := entry.Name()
+		shortObject := shortSHA(object)
+
+		merged, conflicts := remark.MergeNotes(
+			remark.Notes{object: base[object]},
+			remark.Notes{object: ourNotes[object]},
+			remark.Notes{object: theirNotes[object]},
+		)
+
+		if len(conflicts) > 0 && !mergeDriverPreferLatest {
+			unresolved++
+			fmt.Printf("✗ %s: %d conflicting edit%s\n", shortObject, len(conflicts), pluralize(len(conflicts)))
+			printMergeConflicts(conflicts)
+			continue
+		}
+
+		var content []byte
+		if remarks := merged[object]; remarks != nil && !remarks.IsEmpty() {
+			if content, err = remarks.Marshal(); err != nil {
+				return fmt.Errorf("failed to encode remarks: %w", err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(worktree, object), content, 0644); err != nil {
+			return fmt.Errorf("failed to write merged note: %w", err)
+		}
+
+		resolved++
+		if len(conflicts) > 0 {
+			fmt.Printf("✓ %s merged (%d conflicting edit%s kept from the latest side)\n", shortObject, len(conflicts), pluralize(len(conflicts)))
+		} else {
+			fmt.Printf("✓ %s merged\n", shortObject)
+		}
+	}
+
+	if unresolved > 0 {
+		fmt.Printf("\n%d %s manual resolution. Edit them in %s, then run:\n", unresolved, pluralizeWord(unresolved, "note needs", "notes need"), worktree)
+		fmt.Printf("  git notes --ref=%s merge --commit\n", mergeRef)
+		fmt.Println("or rerun with --prefer-latest to keep the most recent edits.")
+		return fmt.Errorf("%d unresolved note%s", unresolved, pluralize(unresolved))
+	}
+
+	if mergeDriverNoCommit {
+		fmt.Printf("\nResolved %d note%s. Commit with: git notes --ref=%s merge --commit\n", resolved, pluralize(resolved), mergeRef)
+		return nil
+	}
+
+	if _, err := git.Run("notes", "--ref="+mergeRef, "merge", "--commit"); err != nil {
+		return fmt.Errorf("failed to commit notes merge: %w", err)
+	}
+	fmt.Printf("✓ Committed notes merge into %s\n", mergeRef)
+
+	return syncSharedAfterMerge(mergeRef, ourNotes)
+}
+
+// syncSharedAfterMerge keeps the private and shared refs consistent
+// after a committed merge into one of them, which 'git notes merge'
+// wrote without updating the other: shared remarks of notes merged into
+// the private ref are mirrored, and private copies of shared remarks
+// the merge removed from the shared ref are removed
+func syncSharedAfterMerge(mergeRef string, before remark.Notes) error {
+	if mergeRef == store.Shared().Ref() {
+		return dropSharedDeletions(before)
+	}
+	s := store.New()
+	if mergeRef != s.Ref() {
+		return nil
+	}
+
+	after, err := store.ListAt(s.Ref())
+	if err != nil {
+		return fmt.Errorf("failed to read merged remarks: %w", err)
+	}
+	var commits []string
+	for commit := range changedNotes(before, after) {
+		commits = append(commits, commit)
+	}
+	sort.Strings(commits)
+
+	if err := s.SyncShared(commits, "Mirrored shared remarks after notes merge"); err != nil {
+		return fmt.Errorf("failed to update %s: %w", store.Shared().Ref(), err)
+	}
+	return nil
+}
+
+// notesMergeRef returns the notes ref a notes merge is merging into
+func notesMergeRef() (string, error) {
+	path, err := git.Run("rev-parse", "--git-path", "NOTES_MERGE_REF")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git directory: %w", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("no notes merge in progress")
+	}
+	return strings.TrimSpace(strings.TrimPrefix(string(content), "ref:")), nil
+}
+
+// printMergeConflicts lists conflicting fields by remark
+func printMergeConflicts(conflicts []remark.Conflict) {
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return conflicts[i].ID < conflicts[j].ID
+	})
+	for _, c := range conflicts {
+		fmt.Printf("    [%s] %s: ours %s, theirs %s\n", c.ID, c.Field, conflictValue(c.Ours), conflictValue(c.Their))
+	}
+}
+
+// conflictValue formats a conflicting field value on one line
+func conflictValue(v any) string {
+	switch value := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", firstLine(value))
+	case *remark.Anchor:
+		if value == nil {
+			return "(none)"
+		}
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
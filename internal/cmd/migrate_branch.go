@@ -43,6 +43,7 @@ func runMigrateBranch(cmd *cobra.Command, args []string) error {
 		for i := range remarks.Remarks {
 			if remarks.Remarks[i].Branch == oldBranch {
 				remarks.Remarks[i].Branch = newBranch
+				remarks.Remarks[i].Touch()
 				needsUpdate = true
 				updatedCount++
 			}
@@ -11,6 +11,8 @@ import (
 	"github.com/Enigama/git-remarks/internal/store"
 )
 
+var migrateRewritesInfer bool
+
 var migrateRewritesCmd = &cobra.Command{
 	Use:    "migrate-rewrites [rewrite-type]",
 	Short:  "Migrate remarks after a rewrite (internal command)",
@@ -18,6 +20,10 @@ var migrateRewritesCmd = &cobra.Command{
 	RunE:   runMigrateRewrites,
 }
 
+func init() {
+	migrateRewritesCmd.Flags().BoolVar(&migrateRewritesInfer, "infer", false, "Infer rewritten commits from the reflog instead of stdin")
+}
+
 func runMigrateRewrites(cmd *cobra.Command, args []string) error {
 	if !git.IsInsideWorkTree() {
 		return nil // Silently exit if not in a git repo
@@ -25,19 +31,39 @@ func runMigrateRewrites(cmd *cobra.Command, args []string) error {
 
 	s := store.New()
 
-	// Read old-sha new-sha pairs from stdin
-	scanner := bufio.NewScanner(os.Stdin)
-	migratedCount := 0
+	var pairs [][2]string
+	if migrateRewritesInfer {
+		// Hook runners such as pre-commit pass the rewrite type in the
+		// environment and do not forward stdin
+		rewriteType := os.Getenv("PRE_COMMIT_REWRITE_COMMAND")
+		if len(args) > 0 {
+			rewriteType = args[0]
+		}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
+		var unmatched []string
+		var err error
+		pairs, unmatched, err = git.InferRewrites(rewriteType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot infer rewritten commits: %v; remarks were not migrated\n", err)
+			return nil
+		}
+		reportUnmatchedRewrites(s, unmatched)
+	} else {
+		// Read old-sha new-sha pairs from stdin
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			parts := strings.Fields(scanner.Text())
+			if len(parts) >= 2 {
+				pairs = append(pairs, [2]string{parts[0], parts[1]})
+			}
 		}
+	}
+
+	migratedCount := 0
 
-		oldSHA := parts[0]
-		newSHA := parts[1]
+	for _, pair := range pairs {
+		oldSHA := pair[0]
+		newSHA := pair[1]
 
 		// Check if old commit has remarks
 		oldRemarks, err := s.Get(oldSHA)
@@ -70,3 +96,16 @@ func runMigrateRewrites(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// reportUnmatchedRewrites warns about rewritten commits with remarks that
+// have no counterpart with the same patch-id. Their remarks stay where
+// they are, to be moved by hand.
+func reportUnmatchedRewrites(s *store.Store, unmatched []string) {
+	for _, commit := range unmatched {
+		remarks, err := s.Get(commit)
+		if err != nil || remarks.IsEmpty() {
+			continue
+		}
+		shortSHA, _ := git.GetShortSHA(commit)
+		fmt.Fprintf(os.Stderr, "Warning: no rewritten commit matches %s; its %d remark(s) were left in place (see 'git remarks move')\n", shortSHA, len(remarks.Remarks))
+	}
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/anchor"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var copyBranch string
+
+var moveCmd = &cobra.Command{
+	Use:   "move <id> <commit>",
+	Short: "Move a remark to another commit",
+	Long: `Move a remark to another commit, keeping its ID and metadata.
+
+If the remark is anchored to a file, the anchor is tracked to the
+target commit.
+
+Examples:
+  git remarks move a1b2c3d4 HEAD~2`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMove,
+}
+
+var copyCmd = &cobra.Command{
+	Use:   "copy <id> <commit>",
+	Short: "Copy a remark to another commit",
+	Long: `Copy a remark to another commit as a new remark.
+
+The copy gets a new ID and creation date and records the ID of the
+remark it was copied from.
+
+Examples:
+  git remarks copy a1b2c3d4 abc1234
+  git remarks copy a1b2c3d4 abc1234 --branch feature/other`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCopy,
+}
+
+func init() {
+	copyCmd.Flags().StringVarP(&copyBranch, "branch", "b", "", "Branch for the copy (default: same as the original)")
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	s := store.New()
+	commit, r, target, err := findRemarkAndTarget(s, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	if commit == target {
+		return fmt.Errorf("remark [%s] is already on %s", r.ID, shortSHA(target))
+	}
+
+	moved := *r
+	moved.Anchor = retargetAnchor(commit, target, r.Anchor)
+
+	found, err := s.MoveRemark(commit, target, moved)
+	if err != nil {
+		return fmt.Errorf("failed to move remark: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("remark not found: %s", r.ID)
+	}
+
+	fmt.Printf("✓ Moved [%s] from %s to %s\n", r.ID, shortSHA(commit), shortSHA(target))
+	return nil
+}
+
+func runCopy(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	s := store.New()
+	commit, r, target, err := findRemarkAndTarget(s, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	branch := r.Branch
+	if copyBranch != "" {
+		branch = copyBranch
+	}
+
+	c := remark.NewRemark(r.Type, branch, r.Body)
+	c.Author = r.Author
+	c.Anchor = retargetAnchor(commit, target, r.Anchor)
+	c.CopiedFrom = r.ID
+
+	if err := s.Add(target, c); err != nil {
+		return fmt.Errorf("failed to copy remark: %w", err)
+	}
+
+	fmt.Printf("✓ Copied [%s] to %s as [%s] (%s)\n", r.ID, shortSHA(target), c.ID, c.Branch)
+	return nil
+}
+
+// findRemarkAndTarget looks up a remark by ID and resolves the target
+// commit to a full SHA
+func findRemarkAndTarget(s *store.Store, remarkID, targetRef string) (string, *remark.Remark, string, error) {
+	target, err := git.Run("rev-parse", "--verify", "--quiet", targetRef+"^{commit}")
+	if err != nil || target == "" {
+		return "", nil, "", fmt.Errorf("invalid commit: %s", targetRef)
+	}
+
+	commit, r, err := s.FindRemarkByID(remarkID)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("failed to find remark: %w", err)
+	}
+
+	if r == nil {
+		return "", nil, "", fmt.Errorf("remark not found: %s", remarkID)
+	}
+
+	return commit, r, target, nil
+}
+
+// retargetAnchor tracks an anchor to another commit. The anchor is
+// dropped if its lines do not exist there.
+func retargetAnchor(from, to string, a *remark.Anchor) *remark.Anchor {
+	tracked := anchor.Retarget(from, to, a)
+	if a != nil && tracked == nil {
+		fmt.Printf("Note: %s does not exist in %s, anchor dropped\n", a, shortSHA(to))
+	}
+	return tracked
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	prSummaryTemplate string
+	prSummaryTodos    string
+)
+
+var prSummaryCmd = &cobra.Command{
+	Use:   "pr-summary [base]",
+	Short: "Generate a pull-request description from branch remarks",
+	Long: `Render the active remarks on base..HEAD for the current branch as a
+Markdown pull-request section, with decisions, open questions (doubts)
+and remaining todos.
+
+The base defaults to main (or master). Unresolved todos cause a warning
+by default; use --todos block to fail instead. The policy can also be
+set with git config remarks.prSummary.todos.
+
+The output can be customised with a Go text/template passed with
+--template or set with git config remarks.prSummary.template. The
+template receives .Branch, .Base, .Decisions, .Doubts, .Todos and
+.Thoughts; each remark has .ID, .Type, .Body, .ShortSHA, .Subject,
+.Anchor, .Author and .CreatedAt.
+
+Examples:
+  git remarks pr-summary
+  git remarks pr-summary develop --todos block
+  git remarks pr-summary --template .github/remarks-pr.tmpl | gh pr create --body-file -`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPRSummary,
+}
+
+func init() {
+	prSummaryCmd.Flags().StringVar(&prSummaryTemplate, "template", "", "Template file (default: built-in Markdown)")
+	prSummaryCmd.Flags().StringVar(&prSummaryTodos, "todos", "", "Unresolved todo policy: warn, block, ignore (default: warn)")
+}
+
+const defaultPRSummaryTemplate = `{{define "items"}}{{range .}}
+- {{indent 2 .Body}} ({{.ShortSHA}})
+{{- end}}{{end -}}
+## Notes for reviewers
+{{- if .Decisions}}
+
+### Decisions
+{{template "items" .Decisions}}
+{{- end}}
+{{- if .Doubts}}
+
+### Open questions
+{{template "items" .Doubts}}
+{{- end}}
+{{- if .Todos}}
+
+### Remaining TODOs
+{{template "items" .Todos}}
+{{- end}}
+{{- if not (or .Decisions .Doubts .Todos)}}
+
+No decisions, open questions or TODOs.
+{{- end}}
+`
+
+// prSummaryData is passed to the pr-summary template
+type prSummaryData struct {
+	Branch    string
+	Base      string
+	Decisions []templateItem
+	Doubts    []templateItem
+	Todos     []templateItem
+	Thoughts  []templateItem
+}
+
+func runPRSummary(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	policy := prSummaryTodos
+	if policy == "" {
+		policy, _ = git.Run("config", "remarks.prSummary.todos")
+	}
+	switch policy {
+	case "":
+		policy = "warn"
+	case "warn", "block", "ignore":
+	default:
+		return fmt.Errorf("invalid todos policy: %s (must be warn, block, or ignore)", policy)
+	}
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("cannot determine current branch: %w", err)
+	}
+
+	base, baseName, err := prSummaryBase(args)
+	if err != nil {
+		return err
+	}
+
+	filter := &remarkFilter{Branch: branch, State: "active", Range: base + "..HEAD"}
+	entries, err := collectRemarks(store.New(), filter)
+	if err != nil {
+		return err
+	}
+
+	// Oldest first reads naturally in a PR description
+	sortEntries(entries, "commit", true)
+
+	data := prSummaryData{Branch: branch, Base: baseName}
+	for _, e := range entries {
+		item := newTemplateItem(e)
+		switch e.Remark.Type {
+		case remark.TypeDecision:
+			data.Decisions = append(data.Decisions, item)
+		case remark.TypeDoubt:
+			data.Doubts = append(data.Doubts, item)
+		case remark.TypeTodo:
+			data.Todos = append(data.Todos, item)
+		default:
+			data.Thoughts = append(data.Thoughts, item)
+		}
+	}
+
+	if len(data.Todos) > 0 && policy != "ignore" {
+		fmt.Fprintf(os.Stderr, "%d unresolved todo%s on %s:\n", len(data.Todos), pluralize(len(data.Todos)), branch)
+		for _, t := range data.Todos {
+			fmt.Fprintf(os.Stderr, "  [%s] %s · %s\n", t.ID, t.ShortSHA, firstLine(t.Body))
+		}
+		if policy == "block" {
+			return fmt.Errorf("resolve the todos above or use --todos warn")
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+
+	output, err := renderTemplate("pr-summary", defaultPRSummaryTemplate, prSummaryTemplate, "remarks.prSummary.template", data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+// prSummaryBase returns the merge-base of the given (or default) base
+// branch with HEAD, and the name to show for it
+func prSummaryBase(args []string) (string, string, error) {
+	if len(args) == 1 {
+		base, err := git.Run("merge-base", args[0], "HEAD")
+		if err != nil {
+			return "", "", fmt.Errorf("invalid base: %s", args[0])
+		}
+		return base, args[0], nil
+	}
+
+	base, err := git.GetDefaultBase()
+	if err != nil {
+		if errors.Is(err, git.ErrNoBaseBranch) {
+			return "", "", fmt.Errorf("no main or master branch found, specify a base: git remarks pr-summary <base>")
+		}
+		return "", "", fmt.Errorf("cannot determine merge-base: %w", err)
+	}
+	return base, shortSHA(base), nil
+}
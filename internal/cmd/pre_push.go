@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var prePushCmd = &cobra.Command{
+	Use:          "pre-push <remote> [url]",
+	Short:        "Check pushed commits for unresolved remarks (internal command)",
+	Hidden:       true,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE:         runPrePush,
+}
+
+func runPrePush(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return nil // Silently exit if not in a git repo
+	}
+
+	policy, _ := git.Run("config", "remarks.prePush")
+	switch policy {
+	case "":
+		policy = "warn"
+	case "warn", "block":
+	case "ignore":
+		return nil
+	default:
+		return fmt.Errorf("invalid remarks.prePush: %s (must be warn, block, or ignore)", policy)
+	}
+
+	allRemarks, err := store.New().ListAllWithRemarks()
+	if err != nil {
+		return fmt.Errorf("failed to list remarks: %w", err)
+	}
+	if len(allRemarks) == 0 {
+		return nil
+	}
+
+	var entries []listEntry
+	seen := make(map[string]bool)
+
+	// Each line is "<local ref> <local sha> <remote ref> <remote sha>"
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		localSHA, remoteSHA := fields[1], fields[3]
+		if strings.Trim(localSHA, "0") == "" {
+			continue // Deleting a remote ref
+		}
+
+		commits, err := git.GetPushedCommits(args[0], localSHA, remoteSHA)
+		if err != nil {
+			return fmt.Errorf("failed to list pushed commits: %w", err)
+		}
+
+		for _, c := range commits {
+			remarks, ok := allRemarks[c.SHA]
+			if !ok || seen[c.SHA] {
+				continue
+			}
+			seen[c.SHA] = true
+
+			for _, r := range remarks.Remarks {
+				if r.State != remark.StateActive || (r.Type != remark.TypeTodo && r.Type != remark.TypeDoubt) {
+					continue
+				}
+				entries = append(entries, listEntry{Commit: c.SHA, ShortSHA: c.ShortSHA, Subject: c.Subject, Remark: r})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read pushed refs: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%d unresolved remark%s on pushed commits:\n", len(entries), pluralize(len(entries)))
+	for _, e := range entries {
+		fmt.Fprintf(os.Stderr, "  [%s] %s · %s · %s\n", e.Remark.ID, e.Remark.Type, e.ShortSHA, firstLine(e.Remark.Body))
+	}
+
+	if policy == "block" {
+		return fmt.Errorf("push blocked by unresolved remarks (resolve them, or push with --no-verify)")
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
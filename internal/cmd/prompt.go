@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var (
+	promptFormat    string
+	promptTypes     []string
+	promptSeparator string
+	promptNoCache   bool
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a compact remark summary for shell prompts",
+	Long: `Print a short summary of active remarks on the current branch, such
+as "3 todo · 1 doubt", for use in a shell prompt.
+
+Counts are cached per worktree and keyed on HEAD, the branch and the
+remarks ref, so a cached prompt does not run git at all. Nothing is
+printed outside a repository, in detached HEAD, or when there are no
+remarks.
+
+With --format, the placeholders {thought}, {doubt}, {todo}, {decision}
+and {total} are replaced by their counts.
+
+Examples:
+  git remarks prompt
+  git remarks prompt --types todo,doubt --separator " "
+  git remarks prompt --format "✎{total}"
+
+  # zsh
+  RPROMPT='$(git remarks prompt)'`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runPrompt,
+}
+
+func init() {
+	promptCmd.Flags().StringVar(&promptFormat, "format", "", "Custom format with {thought}, {doubt}, {todo}, {decision}, {total}")
+	promptCmd.Flags().StringSliceVar(&promptTypes, "types", []string{"todo", "doubt", "decision", "thought"}, "Types to show, in order")
+	promptCmd.Flags().StringVar(&promptSeparator, "separator", " · ", "Separator between types")
+	promptCmd.Flags().BoolVar(&promptNoCache, "no-cache", false, "Ignore and rebuild the cache")
+}
+
+// promptCacheFile is the cache file name inside the per-worktree git dir
+const promptCacheFile = "remarks-prompt.cache"
+
+// promptCache stores remark counts for one HEAD/branch/notes state
+type promptCache struct {
+	Key    string         `json:"key"`
+	Counts map[string]int `json:"counts"`
+}
+
+func runPrompt(cmd *cobra.Command, args []string) error {
+	state, err := git.ReadRepoState(".")
+	if err != nil || state.Branch == "" || state.HEAD == "" {
+		return nil
+	}
+
+	key := strings.Join([]string{
+		state.HEAD,
+		state.Branch,
+		state.ReadRef("refs/notes/" + git.NotesRef),
+		state.ReadRef("refs/notes/" + git.SharedNotesRef),
+	}, " ")
+	cachePath := filepath.Join(state.GitDir, promptCacheFile)
+
+	counts, ok := readPromptCache(cachePath, key)
+	if !ok || promptNoCache {
+		counts, err = countPromptRemarks(state.Branch)
+		if err != nil {
+			return nil
+		}
+		writePromptCache(cachePath, promptCache{Key: key, Counts: counts})
+	}
+
+	if output := formatPrompt(counts); output != "" {
+		fmt.Println(output)
+	}
+	return nil
+}
+
+func readPromptCache(path, key string) (map[string]int, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var cache promptCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Key != key {
+		return nil, false
+	}
+	return cache.Counts, true
+}
+
+// writePromptCache writes the cache atomically; failures are ignored
+func writePromptCache(path string, cache promptCache) {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	os.Rename(tmp, path)
+}
+
+// countPromptRemarks counts active remarks on the branch by type
+func countPromptRemarks(branch string) (map[string]int, error) {
+	filter := &remarkFilter{Branch: branch, State: "active"}
+	entries, err := collectRemarks(store.New(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[string(e.Remark.Type)]++
+	}
+	return counts, nil
+}
+
+// formatPrompt renders the counts with --format, or as a list of
+// non-zero "<count> <type>" items
+func formatPrompt(counts map[string]int) string {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if total == 0 {
+		return ""
+	}
+
+	if promptFormat != "" {
+		replacements := []string{"{total}", strconv.Itoa(total)}
+		for _, t := range remark.Types {
+			replacements = append(replacements, "{"+string(t)+"}", strconv.Itoa(counts[string(t)]))
+		}
+		return strings.NewReplacer(replacements...).Replace(promptFormat)
+	}
+
+	var parts []string
+	for _, t := range promptTypes {
+		if n := counts[t]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, t))
+		}
+	}
+	return strings.Join(parts, promptSeparator)
+}
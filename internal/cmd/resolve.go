@@ -1,35 +1,134 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
 	"github.com/Enigama/git-remarks/internal/store"
 )
 
+var (
+	resolveTypes       []string
+	resolveBranch      string
+	resolveCommit      string
+	resolveAllOnBranch bool
+	resolveInteractive bool
+	resolveYes         bool
+)
+
 var resolveCmd = &cobra.Command{
-	Use:   "resolve <id>",
-	Short: "Resolve (remove) a remark",
-	Long: `Mark a remark as resolved by removing it.
+	Use:   "resolve [id...]",
+	Short: "Mark remarks as resolved",
+	Long: `Mark remarks as resolved. Resolved remarks are hidden from list and
+log, but kept for --state resolved, export and changelog.
+
+Remarks are identified by their IDs (shown in list/show output), or
+selected with filters. Filters apply to active remarks on the current
+branch unless --branch is given, and show a preview to confirm.
 
-The remark is identified by its ID (shown in list/show output).
+With --interactive, each selected remark is shown in turn and can be
+resolved, skipped or edited.
 
 Examples:
-  git remarks resolve a1b2c3d4`,
-	Args: cobra.ExactArgs(1),
+  git remarks resolve a1b2c3d4
+  git remarks resolve a1b2c3d4 b2c3d4e5
+  git remarks resolve --type todo --commit abc1234
+  git remarks resolve --all-on-branch --yes
+  git remarks resolve --interactive`,
 	RunE: runResolve,
 }
 
+func init() {
+	resolveCmd.Flags().StringSliceVar(&resolveTypes, "type", nil, "Resolve remarks of these types (comma-separated)")
+	resolveCmd.Flags().StringVar(&resolveBranch, "branch", "", "Resolve remarks scoped to this branch (default: current branch)")
+	resolveCmd.Flags().StringVar(&resolveCommit, "commit", "", "Resolve remarks on this commit")
+	resolveCmd.Flags().BoolVar(&resolveAllOnBranch, "all-on-branch", false, "Resolve all active remarks on the branch")
+	resolveCmd.Flags().BoolVarP(&resolveInteractive, "interactive", "i", false, "Choose resolve/skip/edit for each remark")
+	resolveCmd.Flags().BoolVarP(&resolveYes, "yes", "y", false, "Do not ask for confirmation")
+}
+
+// resolveTarget is a remark selected for resolution
+type resolveTarget struct {
+	Commit string
+	Remark remark.Remark
+}
+
 func runResolve(cmd *cobra.Command, args []string) error {
 	if !git.IsInsideWorkTree() {
 		return fmt.Errorf("not a git repository")
 	}
 
-	remarkID := args[0]
+	hasFilters := len(resolveTypes) > 0 || resolveBranch != "" || resolveCommit != "" || resolveAllOnBranch
+	if len(args) > 0 && hasFilters {
+		return fmt.Errorf("cannot combine remark IDs with filters")
+	}
+	if len(args) == 0 && !hasFilters && !resolveInteractive {
+		return fmt.Errorf("specify remark IDs, a filter, or --interactive")
+	}
 
 	s := store.New()
 
+	// Single ID: resolve directly
+	if len(args) == 1 && !resolveInteractive {
+		return resolveOne(s, args[0])
+	}
+
+	var targets []resolveTarget
+	var err error
+	if len(args) > 0 {
+		targets, err = selectByIDs(s, args)
+	} else {
+		targets, err = selectByFilters(s)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		fmt.Println("No matching remarks")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	if resolveInteractive {
+		targets, err = chooseInteractively(s, reader, targets)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			fmt.Println("Nothing resolved")
+			return nil
+		}
+	} else if !resolveYes {
+		fmt.Printf("About to resolve %d remark%s:\n\n", len(targets), pluralize(len(targets)))
+		for _, t := range targets {
+			printTargetSummary(t)
+		}
+		fmt.Printf("\nResolve %s? [y/N] ", pluralizeWord(len(targets), "this remark", "these remarks"))
+		if !confirm(reader) {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	resolved, err := resolveTargets(s, targets)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Resolved %d remark%s\n", resolved, pluralize(resolved))
+	return nil
+}
+
+// resolveOne resolves a single remark by ID
+func resolveOne(s *store.Store, remarkID string) error {
 	// Find the remark
 	commit, r, err := s.FindRemarkByID(remarkID)
 	if err != nil {
@@ -40,7 +139,11 @@ func runResolve(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("remark not found: %s", remarkID)
 	}
 
-	// Remove the remark
+	if r.State == remark.StateResolved {
+		fmt.Printf("Already resolved [%s]\n", remarkID)
+		return nil
+	}
+
 	found, err := s.Resolve(commit, remarkID)
 	if err != nil {
 		return fmt.Errorf("failed to resolve remark: %w", err)
@@ -54,3 +157,186 @@ func runResolve(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// selectByIDs finds the remarks with the given IDs
+func selectByIDs(s *store.Store, ids []string) ([]resolveTarget, error) {
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	var targets []resolveTarget
+	for _, id := range ids {
+		found := false
+		for commit, remarks := range allRemarks {
+			if r := remarks.FindByID(id); r != nil {
+				if r.State == remark.StateResolved {
+					return nil, fmt.Errorf("remark already resolved: %s", id)
+				}
+				targets = append(targets, resolveTarget{Commit: commit, Remark: *r})
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("remark not found: %s", id)
+		}
+	}
+	return targets, nil
+}
+
+// selectByFilters finds the active remarks matching the filter flags
+func selectByFilters(s *store.Store) ([]resolveTarget, error) {
+	for _, t := range resolveTypes {
+		if !remark.ValidateType(t) {
+			return nil, fmt.Errorf("invalid type: %s (must be thought, doubt, todo, or decision)", t)
+		}
+	}
+
+	branch := resolveBranch
+	if branch == "" {
+		var err error
+		branch, err = git.GetCurrentBranch()
+		if err != nil {
+			return nil, fmt.Errorf("not on a branch. Use --branch to specify one")
+		}
+	}
+
+	commitFilter := ""
+	if resolveCommit != "" {
+		sha, err := git.Run("rev-parse", "--verify", "--quiet", resolveCommit+"^{commit}")
+		if err != nil || sha == "" {
+			return nil, fmt.Errorf("invalid commit: %s", resolveCommit)
+		}
+		commitFilter = sha
+	}
+
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	var targets []resolveTarget
+	for commit, remarks := range allRemarks {
+		if commitFilter != "" && commit != commitFilter {
+			continue
+		}
+		for _, r := range remarks.ActiveForBranch(branch) {
+			if len(resolveTypes) > 0 && !containsType(resolveTypes, r.Type) {
+				continue
+			}
+			targets = append(targets, resolveTarget{Commit: commit, Remark: r})
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Remark.CreatedAt.Before(targets[j].Remark.CreatedAt)
+	})
+	return targets, nil
+}
+
+// chooseInteractively asks what to do with each target and returns the
+// ones to resolve
+func chooseInteractively(s *store.Store, reader *bufio.Reader, targets []resolveTarget) ([]resolveTarget, error) {
+	var chosen []resolveTarget
+
+	for i := 0; i < len(targets); i++ {
+		t := targets[i]
+		fmt.Printf("\n(%d/%d) ", i+1, len(targets))
+		printTargetSummary(t)
+		fmt.Printf("[r]esolve, [s]kip, [e]dit, [q]uit? ")
+
+		response, _ := reader.ReadString('\n')
+		switch strings.TrimSpace(strings.ToLower(response)) {
+		case "r", "resolve":
+			chosen = append(chosen, t)
+		case "e", "edit":
+			edited, err := editTarget(s, t)
+			if err != nil {
+				return nil, err
+			}
+			targets[i] = edited
+			i-- // ask again about the edited remark
+		case "q", "quit":
+			return chosen, nil
+		default:
+			fmt.Println("Skipped")
+		}
+	}
+
+	return chosen, nil
+}
+
+// editTarget opens a remark in the editor and saves the result
+func editTarget(s *store.Store, t resolveTarget) (resolveTarget, error) {
+	newBody, newType, err := openEditorForEdit(shortSHA(t.Commit), &t.Remark)
+	if err != nil {
+		return t, err
+	}
+
+	if strings.TrimSpace(newBody) == "" {
+		fmt.Println("Empty body, remark not changed")
+		return t, nil
+	}
+
+	t.Remark.Body = newBody
+	if newType != "" && remark.ValidateType(newType) {
+		t.Remark.Type = remark.Type(newType)
+	}
+
+	if err := s.UpdateRemark(t.Commit, t.Remark); err != nil {
+		return t, fmt.Errorf("failed to update remark: %w", err)
+	}
+
+	fmt.Printf("✓ Updated [%s]\n", t.Remark.ID)
+	return t, nil
+}
+
+// resolveTargets resolves the targets with one write per commit
+func resolveTargets(s *store.Store, targets []resolveTarget) (int, error) {
+	byCommit := make(map[string][]string)
+	var commits []string
+	for _, t := range targets {
+		if _, ok := byCommit[t.Commit]; !ok {
+			commits = append(commits, t.Commit)
+		}
+		byCommit[t.Commit] = append(byCommit[t.Commit], t.Remark.ID)
+	}
+
+	resolved := 0
+	for _, commit := range commits {
+		n, err := s.ResolveIDs(commit, byCommit[commit])
+		if err != nil {
+			return resolved, fmt.Errorf("failed to resolve remarks on %s: %w", shortSHA(commit), err)
+		}
+		resolved += n
+	}
+	return resolved, nil
+}
+
+// printTargetSummary prints one line describing a remark
+func printTargetSummary(t resolveTarget) {
+	fmt.Printf("[%s] %s · %s · %s · %s\n", t.Remark.ID, t.Remark.Type, formatAge(t.Remark.CreatedAt), shortSHA(t.Commit), firstLine(t.Remark.Body))
+}
+
+func containsType(types []string, t remark.Type) bool {
+	for _, candidate := range types {
+		if remark.Type(candidate) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// confirm reads a yes/no answer, defaulting to no
+func confirm(reader *bufio.Reader) bool {
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+func pluralizeWord(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
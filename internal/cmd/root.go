@@ -30,12 +30,35 @@ func Execute() error {
 func init() {
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(commitCmd)
 	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(logCmd)
+	rootCmd.AddCommand(blameCmd)
+	rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(resolveCmd)
 	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(whereCmd)
+	rootCmd.AddCommand(moveCmd)
+	rootCmd.AddCommand(copyCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(prSummaryCmd)
+	rootCmd.AddCommand(changelogCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(importNotesCmd)
+	rootCmd.AddCommand(shareCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(mergeDriverCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(uninitCmd)
+	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(recoverCmd)
 	rootCmd.AddCommand(migrateBranchCmd)
 	rootCmd.AddCommand(migrateRewritesCmd)
+	rootCmd.AddCommand(prePushCmd)
+	rootCmd.AddCommand(commitMsgCmd)
+	rootCmd.AddCommand(postCommitCmd)
 }
 
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var sharePrivate bool
+
+var shareCmd = &cobra.Command{
+	Use:   "share <id>...",
+	Short: "Share remarks with collaborators",
+	Long: `Mark remarks as shared, or private again with --private.
+
+Shared remarks are mirrored to refs/notes/remarks-shared, the only ref
+that sync pushes. Private remarks stay in refs/notes/remarks and never
+leave the repository.
+
+Making a remark private removes it from the shared ref locally; copies
+already pushed stay on the remote until the next sync.
+
+Examples:
+  git remarks share a1b2c3d4
+  git remarks share a1b2c3d4 b2c3d4e5
+  git remarks share --private a1b2c3d4`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runShare,
+}
+
+func init() {
+	shareCmd.Flags().BoolVar(&sharePrivate, "private", false, "Make the remarks private again")
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	visibility := remark.VisibilityShared
+	if sharePrivate {
+		visibility = remark.VisibilityPrivate
+	}
+
+	s := store.New()
+	for _, id := range args {
+		commit, r, err := s.FindRemarkByID(id)
+		if err != nil {
+			return fmt.Errorf("failed to find remark: %w", err)
+		}
+		if r == nil {
+			return fmt.Errorf("remark not found: %s", id)
+		}
+
+		if r.IsShared() != sharePrivate {
+			fmt.Printf("Already %s [%s]\n", visibility, id)
+			continue
+		}
+
+		// Private is the default and is not stored
+		r.Visibility = ""
+		if !sharePrivate {
+			r.Visibility = remark.VisibilityShared
+		}
+		if err := s.UpdateRemark(commit, *r); err != nil {
+			return fmt.Errorf("failed to update remark: %w", err)
+		}
+
+		if sharePrivate {
+			fmt.Printf("✓ Made [%s] private\n", id)
+		} else {
+			fmt.Printf("✓ Shared [%s]\n", id)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
 	"github.com/Enigama/git-remarks/internal/store"
 )
 
@@ -72,10 +73,21 @@ func runShow(cmd *cobra.Command, args []string) error {
 		if r.State == "resolved" {
 			stateIndicator = " [resolved]"
 		}
+		if r.IsShared() {
+			stateIndicator += " [shared]"
+		}
+
+		if r.CopiedFrom != "" {
+			stateIndicator += fmt.Sprintf(" (copied from [%s])", r.CopiedFrom)
+		}
 
 		age := formatAge(r.CreatedAt)
 		fmt.Printf("[%s] %s · %s · %s%s\n", r.ID, r.Type, age, r.Branch, stateIndicator)
 
+		if r.Anchor != nil {
+			printAnchor(fullSHA, r.Anchor)
+		}
+
 		// Indent the body
 		lines := strings.Split(strings.TrimSpace(r.Body), "\n")
 		for _, line := range lines {
@@ -87,3 +99,26 @@ func runShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+
+// printAnchor prints the anchored location and the referenced code
+// as it was in the given commit
+func printAnchor(commit string, anchor *remark.Anchor) {
+	fmt.Printf("  @ %s\n", anchor)
+	if !anchor.HasLines() {
+		return
+	}
+
+	content, err := git.ReadFileAt(commit, anchor.Path)
+	if err != nil {
+		fmt.Printf("  (file not available in this commit)\n")
+		return
+	}
+
+	lines := splitLines(content)
+	width := len(fmt.Sprint(anchor.EndLine))
+	for n := anchor.StartLine; n <= anchor.EndLine && n <= len(lines); n++ {
+		gutter := fmt.Sprintf("%*d │", width, n)
+		fmt.Printf("  %s %s\n", colorize(colorDim, gutter), lines[n-1])
+	}
+	fmt.Println()
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var statsJSON bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about remarks",
+	Long: `Show a summary of all remarks in the repository.
+
+Includes totals by type, state, branch and author, the age of active
+remarks, the oldest unresolved todos, remarks per commit and the
+branches with the most open doubts.
+
+Examples:
+  git remarks stats
+  git remarks stats --json`,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Output as JSON")
+}
+
+// statsTopN is the number of entries shown in top lists
+const statsTopN = 5
+
+// ageBuckets are the age ranges used for the age distribution
+var ageBuckets = []struct {
+	Label string
+	Max   time.Duration
+}{
+	{"< 1 day", 24 * time.Hour},
+	{"1-7 days", 7 * 24 * time.Hour},
+	{"1-4 weeks", 28 * 24 * time.Hour},
+	{"1-3 months", 90 * 24 * time.Hour},
+	{"> 3 months", 0},
+}
+
+// statsReport is the summary printed by the stats command
+type statsReport struct {
+	Total          int            `json:"total"`
+	Commits        int            `json:"commits"`
+	ByType         map[string]int `json:"by_type"`
+	ByState        map[string]int `json:"by_state"`
+	ByBranch       map[string]int `json:"by_branch"`
+	ByAuthor       map[string]int `json:"by_author"`
+	ActiveByAge    []countEntry   `json:"active_by_age"`
+	OldestTodos    []todoEntry    `json:"oldest_todos"`
+	PerCommit      perCommitStats `json:"per_commit"`
+	DoubtsByBranch []countEntry   `json:"open_doubts_by_branch"`
+}
+
+type countEntry struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type todoEntry struct {
+	ID        string    `json:"id"`
+	Commit    string    `json:"commit"`
+	Branch    string    `json:"branch"`
+	CreatedAt time.Time `json:"created_at"`
+	Body      string    `json:"body"`
+}
+
+type perCommitStats struct {
+	Average   float64 `json:"average"`
+	Max       int     `json:"max"`
+	MaxCommit string  `json:"max_commit,omitempty"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	s := store.New()
+	allRemarks, err := s.ListAllWithRemarks()
+	if err != nil {
+		return fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	report := computeStats(allRemarks, time.Now())
+
+	if statsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+
+	printStats(report)
+	return nil
+}
+
+// computeStats builds a statsReport from all remarks in the repository
+func computeStats(allRemarks map[string]*remark.Remarks, now time.Time) statsReport {
+	report := statsReport{
+		Commits:  len(allRemarks),
+		ByType:   make(map[string]int),
+		ByState:  make(map[string]int),
+		ByBranch: make(map[string]int),
+		ByAuthor: make(map[string]int),
+	}
+
+	ages := make([]int, len(ageBuckets))
+	doubts := make(map[string]int)
+
+	for commit, remarks := range allRemarks {
+		count := len(remarks.Remarks)
+		if count > report.PerCommit.Max || (count == report.PerCommit.Max && commit < report.PerCommit.MaxCommit) {
+			report.PerCommit.Max = count
+			report.PerCommit.MaxCommit = commit
+		}
+
+		for _, r := range remarks.Remarks {
+			report.Total++
+			report.ByType[string(r.Type)]++
+			report.ByState[string(r.State)]++
+			report.ByBranch[r.Branch]++
+
+			author := r.Author
+			if author == "" {
+				author = "unknown"
+			}
+			report.ByAuthor[author]++
+
+			if r.State != remark.StateActive {
+				continue
+			}
+
+			ages[ageBucket(now.Sub(r.CreatedAt))]++
+
+			switch r.Type {
+			case remark.TypeTodo:
+				report.OldestTodos = append(report.OldestTodos, todoEntry{
+					ID:        r.ID,
+					Commit:    commit,
+					Branch:    r.Branch,
+					CreatedAt: r.CreatedAt,
+					Body:      r.Body,
+				})
+			case remark.TypeDoubt:
+				doubts[r.Branch]++
+			}
+		}
+	}
+
+	if report.Commits > 0 {
+		report.PerCommit.Average = float64(report.Total) / float64(report.Commits)
+	}
+
+	for i, bucket := range ageBuckets {
+		report.ActiveByAge = append(report.ActiveByAge, countEntry{Name: bucket.Label, Count: ages[i]})
+	}
+
+	sort.Slice(report.OldestTodos, func(i, j int) bool {
+		return report.OldestTodos[i].CreatedAt.Before(report.OldestTodos[j].CreatedAt)
+	})
+	if len(report.OldestTodos) > statsTopN {
+		report.OldestTodos = report.OldestTodos[:statsTopN]
+	}
+
+	report.DoubtsByBranch = topCounts(doubts, statsTopN)
+
+	return report
+}
+
+// ageBucket returns the index of the age bucket for a duration
+func ageBucket(age time.Duration) int {
+	for i, bucket := range ageBuckets {
+		if bucket.Max == 0 || age < bucket.Max {
+			return i
+		}
+	}
+	return len(ageBuckets) - 1
+}
+
+// topCounts returns the n largest counts, largest first
+func topCounts(counts map[string]int, n int) []countEntry {
+	entries := make([]countEntry, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, countEntry{Name: name, Count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+func printStats(report statsReport) {
+	if report.Total == 0 {
+		fmt.Println("No remarks")
+		return
+	}
+
+	fmt.Printf("%d remark%s on %d commit%s\n", report.Total, pluralize(report.Total), report.Commits, pluralize(report.Commits))
+
+	printCounts("By type", topCounts(report.ByType, 0))
+	printCounts("By state", topCounts(report.ByState, 0))
+	printCounts("By branch", topCounts(report.ByBranch, 0))
+	printCounts("By author", topCounts(report.ByAuthor, 0))
+	printCounts("Active remarks by age", report.ActiveByAge)
+
+	fmt.Printf("\nRemarks per commit\n")
+	fmt.Printf("  average  %.1f\n", report.PerCommit.Average)
+	fmt.Printf("  max      %d (%s)\n", report.PerCommit.Max, shortSHA(report.PerCommit.MaxCommit))
+
+	if len(report.OldestTodos) > 0 {
+		fmt.Printf("\nOldest unresolved todos\n")
+		for _, t := range report.OldestTodos {
+			fmt.Printf("  [%s] %s · %s · %s\n", t.ID, formatAge(t.CreatedAt), shortSHA(t.Commit), firstLine(t.Body))
+		}
+	}
+
+	if len(report.DoubtsByBranch) > 0 {
+		printCounts("Open doubts by branch", report.DoubtsByBranch)
+	}
+}
+
+// printCounts prints a titled table of counts
+func printCounts(title string, entries []countEntry) {
+	fmt.Printf("\n%s\n", title)
+
+	width := 0
+	for _, e := range entries {
+		if len(e.Name) > width {
+			width = len(e.Name)
+		}
+	}
+
+	for _, e := range entries {
+		fmt.Printf("  %-*s  %d\n", width, e.Name, e.Count)
+	}
+}
+
+// shortSHA abbreviates a full SHA without calling git
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// firstLine returns the first line of a remark body
+func firstLine(body string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	return line
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var syncNoPush bool
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [remote]",
+	Short: "Fetch, merge and push shared remarks with a remote",
+	Long: `Synchronise shared remarks with a remote (default: origin).
+
+Only refs/notes/remarks-shared is transferred; private remarks never
+leave the repository. The remote's shared ref is fetched into
+refs/notes/remotes/<remote>/remarks-shared and merged into the local one.
+Remarks are matched by ID: new remarks from either side are kept, each
+field takes the side that changed it (or the most recently modified
+side if both did), and resolved remarks stay resolved. Shared remarks
+the merge removes are removed from the local private ref too. The
+result is then pushed back.
+
+Examples:
+  git remarks sync
+  git remarks sync backup --no-push`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncNoPush, "no-push", false, "Fetch and merge only")
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	remote := "origin"
+	if len(args) == 1 {
+		remote = args[0]
+	}
+	if _, err := git.Run("remote", "get-url", remote); err != nil {
+		return fmt.Errorf("no such remote: %s", remote)
+	}
+
+	s := store.Shared()
+	tracking := store.NewWithRef(remoteTrackingRef(remote))
+
+	remoteTip, err := fetchNotes(remote, s.Ref(), tracking.Ref())
+	if err != nil {
+		return err
+	}
+
+	before, err := notesAt(s.Tip())
+	if err != nil {
+		return fmt.Errorf("failed to read local remarks: %w", err)
+	}
+	if err := mergeNotes(s, remoteTip, remote); err != nil {
+		return err
+	}
+	if err := dropSharedDeletions(before); err != nil {
+		return err
+	}
+
+	if syncNoPush {
+		return nil
+	}
+
+	localTip := s.Tip()
+	if localTip == "" || localTip == remoteTip {
+		fmt.Printf("✓ Shared remarks up to date with %s\n", remote)
+		return nil
+	}
+
+	if _, err := git.Run("push", "--quiet", remote, s.Ref()+":"+s.Ref()); err != nil {
+		return fmt.Errorf("failed to push remarks (run sync again if %s changed meanwhile): %w", remote, err)
+	}
+	if err := tracking.SetTip(localTip); err != nil {
+		return fmt.Errorf("failed to update %s: %w", tracking.Ref(), err)
+	}
+
+	fmt.Printf("✓ Pushed shared remarks to %s\n", remote)
+	return nil
+}
+
+// notesAt returns the remarks in a notes commit, or none if tip is empty
+func notesAt(tip string) (remark.Notes, error) {
+	if tip == "" {
+		return remark.Notes{}, nil
+	}
+	return store.ListAt(tip)
+}
+
+// dropSharedDeletions removes the private copies of shared remarks that
+// were in the shared ref before a merge into it and are gone after it
+func dropSharedDeletions(before remark.Notes) error {
+	s := store.New()
+	dropped, err := s.DropSharedDeletions(before, "Removed remarks deleted from the shared ref")
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", s.Ref(), err)
+	}
+	if dropped > 0 {
+		fmt.Printf("✓ Removed %d shared remark%s deleted elsewhere\n", dropped, pluralize(dropped))
+	}
+	return nil
+}
+
+// remoteTrackingRef is where a remote's shared remarks are fetched to
+func remoteTrackingRef(remote string) string {
+	return "refs/notes/remotes/" + remote + "/" + git.SharedNotesRef
+}
+
+// fetchNotes fetches a remote's notes ref into the tracking ref and
+// returns its tip, or an empty string if the remote has no remarks
+func fetchNotes(remote, ref, tracking string) (string, error) {
+	_, err := git.Run("fetch", "--quiet", remote, "+"+ref+":"+tracking)
+	if err != nil {
+		if strings.Contains(err.Error(), "couldn't find remote ref") {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch remarks from %s: %w", remote, err)
+	}
+	return store.NewWithRef(tracking).Tip(), nil
+}
+
+// mergeNotes merges a fetched notes commit into the store's ref
+func mergeNotes(s *store.Store, remoteTip, remote string) error {
+	localTip := s.Tip()
+
+	switch {
+	case remoteTip == "" || remoteTip == localTip:
+		return nil
+	case localTip == "":
+		if err := s.SetTip(remoteTip); err != nil {
+			return fmt.Errorf("failed to update %s: %w", s.Ref(), err)
+		}
+		fmt.Printf("✓ Fetched shared remarks from %s\n", remote)
+		return nil
+	}
+
+	if ahead, _ := git.IsAncestor(remoteTip, localTip); ahead {
+		return nil
+	}
+	if behind, _ := git.IsAncestor(localTip, remoteTip); behind {
+		if err := s.SetTip(remoteTip); err != nil {
+			return fmt.Errorf("failed to update %s: %w", s.Ref(), err)
+		}
+		fmt.Printf("✓ Fast-forwarded shared remarks from %s\n", remote)
+		return nil
+	}
+
+	base := remark.Notes{}
+	if mergeBase, err := git.Run("merge-base", localTip, remoteTip); err == nil && mergeBase != "" {
+		if base, err = store.ListAt(mergeBase); err != nil {
+			return fmt.Errorf("failed to read merge-base remarks: %w", err)
+		}
+	}
+	ours, err := store.ListAt(localTip)
+	if err != nil {
+		return fmt.Errorf("failed to read local remarks: %w", err)
+	}
+	theirs, err := store.ListAt(remoteTip)
+	if err != nil {
+		return fmt.Errorf("failed to read remarks from %s: %w", remote, err)
+	}
+
+	merged, conflicts := remark.MergeNotes(base, ours, theirs)
+	batch := changedNotes(ours, merged)
+
+	if err := s.SaveMerge(batch, "Merged remarks from "+remote, remoteTip); err != nil {
+		return fmt.Errorf("failed to write merged remarks: %w", err)
+	}
+
+	fmt.Printf("✓ Merged shared remarks from %s (%d commit%s updated)\n", remote, len(batch), pluralize(len(batch)))
+	for _, c := range conflicts {
+		fmt.Printf("  [%s] %s edited on both sides, kept the latest change\n", c.ID, c.Field)
+	}
+	return nil
+}
+
+// changedNotes returns the notes in merged that differ from current,
+// with nil entries for notes to remove
+func changedNotes(current, merged remark.Notes) map[string]*remark.Remarks {
+	batch := make(map[string]*remark.Remarks)
+	for commit, remarks := range merged {
+		if !sameNote(current[commit], remarks) {
+			batch[commit] = remarks
+		}
+	}
+	for commit := range current {
+		if _, ok := merged[commit]; !ok {
+			batch[commit] = nil
+		}
+	}
+	return batch
+}
+
+// sameNote compares two notes by their serialised form
+func sameNote(a, b *remark.Remarks) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	da, errA := a.Marshal()
+	db, errB := b.Marshal()
+	return errA == nil && errB == nil && bytes.Equal(da, db)
+}
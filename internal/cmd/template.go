@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Enigama/git-remarks/internal/git"
+)
+
+// templateItem is a remark as exposed to output templates
+type templateItem struct {
+	ID        string
+	Type      string
+	State     string
+	Branch    string
+	Author    string
+	Commit    string
+	ShortSHA  string
+	Subject   string
+	Body      string
+	Anchor    string
+	CreatedAt time.Time
+}
+
+func newTemplateItem(e listEntry) templateItem {
+	item := templateItem{
+		ID:        e.Remark.ID,
+		Type:      string(e.Remark.Type),
+		State:     string(e.Remark.State),
+		Branch:    e.Remark.Branch,
+		Author:    e.Remark.Author,
+		Commit:    e.Commit,
+		ShortSHA:  e.ShortSHA,
+		Subject:   e.Subject,
+		Body:      strings.TrimSpace(e.Remark.Body),
+		CreatedAt: e.Remark.CreatedAt,
+	}
+	if e.Remark.Anchor != nil {
+		item.Anchor = e.Remark.Anchor.String()
+	}
+	return item
+}
+
+// templateFuncs are available in all output templates
+var templateFuncs = template.FuncMap{
+	// indent indents every line but the first by n spaces
+	"indent": func(n int, s string) string {
+		return strings.ReplaceAll(s, "\n", "\n"+strings.Repeat(" ", n))
+	},
+	"firstLine": firstLine,
+	"inc": func(i int) int {
+		return i + 1
+	},
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+}
+
+// renderTemplate renders data with the template in path, falling back to
+// the template file named by the git config key, then to the built-in
+// default
+func renderTemplate(name, defaultText, path, configKey string, data any) (string, error) {
+	text := defaultText
+
+	if path == "" && configKey != "" {
+		path, _ = git.Run("config", "--path", configKey)
+	}
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template: %w", err)
+		}
+		text = string(content)
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/store"
+	"github.com/Enigama/git-remarks/internal/tui"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and manage remarks in a full-screen terminal UI",
+	Long: `Open a full-screen browser with the commit history on the left and the
+selected commit's remarks on the right.
+
+Keys:
+  j/k, ↑/↓   move        tab   switch pane
+  a          add         e     edit in $EDITOR
+  r          resolve     t     change type
+  m          move        f     filter by type
+  b          branch scope
+  d          show diff   q     quit
+
+Examples:
+  git remarks tui`,
+	Args: cobra.NoArgs,
+	RunE: runTUI,
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil && !errors.Is(err, git.ErrDetachedHead) {
+		return fmt.Errorf("cannot determine current branch: %w", err)
+	}
+
+	term, err := tui.OpenTTY()
+	if err != nil {
+		return err
+	}
+	defer term.Close()
+
+	app := tui.New(term, tui.GitHistory{}, store.New(), tui.Options{
+		Branch: branch,
+		Author: git.GetUserIdent(),
+		Edit:   openEditorForEdit,
+	})
+	return app.Run()
+}
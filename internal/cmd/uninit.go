@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/hook"
+)
+
+var uninitGlobal bool
+
+var uninitCmd = &cobra.Command{
+	Use:   "uninit",
+	Short: "Remove git-remarks hooks from the current repository",
+	Long: `Remove the git-remarks blocks from the post-rewrite hook and
+any other hooks installed by init, such as pre-push.
+
+Only the blocks installed by 'git remarks init' are removed; the rest
+of each hook is left as it is. If nothing else is left, the hook file
+is deleted. Remarks themselves are not affected.
+
+With --global, the hooks are removed from the global core.hooksPath or
+init.templateDir template instead.
+
+Examples:
+  git remarks uninit
+  git remarks uninit --global`,
+	Args: cobra.NoArgs,
+	RunE: runUninit,
+}
+
+func init() {
+	uninitCmd.Flags().BoolVar(&uninitGlobal, "global", false, "Remove the hooks installed by init --global")
+}
+
+func runUninit(cmd *cobra.Command, args []string) error {
+	var hooksDir string
+	var err error
+	if uninitGlobal {
+		hooksDir, _, err = globalHooksDir(false)
+		if err != nil {
+			return err
+		}
+	} else {
+		if !git.IsInsideWorkTree() {
+			return fmt.Errorf("not a git repository")
+		}
+		hooksDir, err = git.GetHooksDir()
+		if err != nil {
+			return fmt.Errorf("failed to get hooks directory: %w", err)
+		}
+	}
+
+	found := 0
+	for _, h := range managedHooks {
+		hookPath := filepath.Join(hooksDir, h.Name)
+		result, err := hook.Uninstall(hookPath)
+		if err != nil {
+			return err
+		}
+
+		switch result {
+		case hook.Removed:
+			fmt.Printf("✓ git-remarks %s hook removed\n", h.Name)
+			found++
+		case hook.Manual:
+			fmt.Printf("%s calls git-remarks outside a managed block; edit it by hand\n", hookPath)
+			found++
+		}
+	}
+
+	if found == 0 {
+		fmt.Println("git-remarks hooks are not installed")
+	}
+	return nil
+}
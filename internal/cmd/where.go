@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/Enigama/git-remarks/internal/anchor"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/store"
+)
+
+var whereCmd = &cobra.Command{
+	Use:   "where <id>",
+	Short: "Show where an anchored remark's lines are now",
+	Long: `Track the file and line range of an anchored remark from the commit
+it is attached to up to HEAD, following renames and line movement.
+
+If the anchored lines have been deleted, the remark can most likely
+be resolved.
+
+Examples:
+  git remarks where a1b2c3d4`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWhere,
+}
+
+func runWhere(cmd *cobra.Command, args []string) error {
+	if !git.IsInsideWorkTree() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	remarkID := args[0]
+
+	s := store.New()
+	commit, r, err := s.FindRemarkByID(remarkID)
+	if err != nil {
+		return fmt.Errorf("failed to find remark: %w", err)
+	}
+
+	if r == nil {
+		return fmt.Errorf("remark not found: %s", remarkID)
+	}
+
+	if r.Anchor == nil {
+		return fmt.Errorf("remark [%s] is not anchored to a file", remarkID)
+	}
+
+	head, err := git.GetHEAD()
+	if err != nil {
+		return fmt.Errorf("cannot get HEAD: %w", err)
+	}
+
+	tracked, changed, err := anchor.Track(commit, head, r.Anchor)
+	if err != nil {
+		return fmt.Errorf("failed to track lines: %w", err)
+	}
+
+	shortSHA, _ := git.GetShortSHA(commit)
+	fmt.Printf("[%s] %s in %s\n", r.ID, r.Anchor, shortSHA)
+
+	if tracked == nil {
+		fmt.Println("  Deleted in HEAD — this remark can probably be resolved:")
+		fmt.Printf("  git remarks resolve %s\n", r.ID)
+		return nil
+	}
+
+	status := "unchanged"
+	switch {
+	case changed:
+		status = "modified"
+	case *tracked != *r.Anchor:
+		status = "moved"
+	}
+	fmt.Printf("  Now at %s in HEAD (%s)\n\n", tracked, status)
+
+	if tracked.HasLines() {
+		printAnchor(head, tracked)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+// Package format holds the formatting shared by the command line output
+// and the TUI, so both present remarks the same way.
+package format
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Enigama/git-remarks/internal/remark"
+)
+
+// ANSI escape codes for the colors used for remark types
+const (
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+	Cyan   = "\033[36m"
+)
+
+// TypeColor returns the ANSI color used for a remark type
+func TypeColor(t remark.Type) string {
+	switch t {
+	case remark.TypeDoubt:
+		return Yellow
+	case remark.TypeTodo:
+		return Red
+	case remark.TypeDecision:
+		return Green
+	default:
+		return Cyan
+	}
+}
+
+// Age describes how long ago t was, e.g. "3h ago"
+func Age(t time.Time) string {
+	d := time.Since(t)
+
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// Plural returns the plural suffix "s" unless n is 1
+func Plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
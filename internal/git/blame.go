@@ -0,0 +1,57 @@
+package git
+
+import (
+	"strings"
+)
+
+// BlameLine is a single line of a file with the commit that last touched it
+type BlameLine struct {
+	Commit  string
+	Line    int
+	Content string
+}
+
+// Blame returns the lines of a file as of the given commit together with
+// the commit that last changed each line
+func Blame(commit, path string) ([]BlameLine, error) {
+	// path is relative to the repository root, as blame resolves it
+	// against the working directory
+	root, err := GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	output, err := RunRaw("-C", root, "blame", "--porcelain", commit, "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []BlameLine
+	var current string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			lines = append(lines, BlameLine{
+				Commit:  current,
+				Line:    len(lines) + 1,
+				Content: strings.TrimPrefix(line, "\t"),
+			})
+			continue
+		}
+
+		// Header line: <sha> <orig-line> <final-line> [<group-size>]
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && len(fields[0]) >= 40 && isHex(fields[0]) {
+			current = fields[0]
+		}
+	}
+
+	return lines, nil
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
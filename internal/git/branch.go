@@ -2,12 +2,20 @@ package git
 
 import (
 	"errors"
+	"os"
+	"strconv"
 	"strings"
 )
 
 // ErrDetachedHead is returned when the repository is in detached HEAD state
 var ErrDetachedHead = errors.New("not on a branch (detached HEAD)")
 
+// ErrNoBaseBranch is returned when no main branch can be found
+var ErrNoBaseBranch = errors.New("no main or master branch found")
+
+// baseBranches lists the refs tried, in order, when looking for the main branch
+var baseBranches = []string{"main", "master", "origin/main", "origin/master"}
+
 // GetCurrentBranch returns the current branch name
 func GetCurrentBranch() (string, error) {
 	output, err := Run("symbolic-ref", "--short", "HEAD")
@@ -21,6 +29,25 @@ func GetCurrentBranch() (string, error) {
 	return output, nil
 }
 
+// GetRebaseBranch returns the branch being rebased while HEAD is
+// detached by a rebase, or ErrDetachedHead if no rebase is in progress
+func GetRebaseBranch() (string, error) {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		path, err := Run("rev-parse", "--git-path", dir+"/head-name")
+		if err != nil {
+			return "", err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if name := strings.TrimSpace(string(content)); strings.HasPrefix(name, "refs/heads/") {
+			return strings.TrimPrefix(name, "refs/heads/"), nil
+		}
+	}
+	return "", ErrDetachedHead
+}
+
 // IsDetachedHead returns true if the repository is in detached HEAD state
 func IsDetachedHead() bool {
 	_, err := GetCurrentBranch()
@@ -42,7 +69,7 @@ func GetShortSHA(commit string) (string, error) {
 func GetAncestors(commit string, limit int) ([]string, error) {
 	args := []string{"rev-list", commit}
 	if limit > 0 {
-		args = append(args, "-n", string(rune(limit+'0')))
+		args = append(args, "-n", strconv.Itoa(limit))
 	}
 
 	output, err := Run(args...)
@@ -87,16 +114,146 @@ func IsAncestor(ancestor, descendant string) (bool, error) {
 func GetCommitLog(commit string, limit int) ([]CommitInfo, error) {
 	args := []string{"log", "--format=%H %h %s", commit}
 	if limit > 0 {
-		args = append(args, "-n", string(rune(limit+'0')))
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+
+	output, err := Run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommitLog(output), nil
+}
+
+// GetLog returns the commits selected by the given revisions, as
+// accepted by git log (e.g. "main..HEAD")
+func GetLog(revs []string, limit int) ([]CommitInfo, error) {
+	args := []string{"log", "--format=%H %h %s"}
+	if limit > 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+	args = append(args, revs...)
+	args = append(args, "--")
+
+	output, err := Run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommitLog(output), nil
+}
+
+// GetDefaultBase returns the merge-base of HEAD and the main branch
+func GetDefaultBase() (string, error) {
+	for _, branch := range baseBranches {
+		if _, err := Run("rev-parse", "--verify", "--quiet", branch+"^{commit}"); err != nil {
+			continue
+		}
+		return Run("merge-base", branch, "HEAD")
+	}
+	return "", ErrNoBaseBranch
+}
+
+// GetReflogTime returns the time of the reflog entry a ref such as
+// "feature/x@{1}" or "main@{yesterday}" names, in a form accepted by
+// ref@{<date>}. ok is false if the ref does not name a reflog entry.
+func GetReflogTime(ref string) (when string, ok bool) {
+	i := strings.Index(ref, "@{")
+	j := strings.Index(ref, "}")
+	if i < 0 || j < i {
+		return "", false
+	}
+	name, selector := ref[:i], ref[i+2:j]
+	if name == "" {
+		name = "HEAD"
+	}
+
+	switch {
+	case selector == "" || strings.HasPrefix(selector, "-"):
+		return "", false // @{-1} is a branch, not an entry
+	case selector == "u" || selector == "upstream" || selector == "push":
+		return "", false
+	}
+
+	n, err := strconv.Atoi(selector)
+	if err != nil {
+		return selector, true // Already a date
+	}
+
+	output, err := Run("log", "-g", "--date=unix", "--format=%gd", "-n", strconv.Itoa(n+1), name)
+	if err != nil {
+		return "", false
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) <= n {
+		return "", false
+	}
+	entry := lines[n]
+	if k := strings.LastIndex(entry, "@{"); k >= 0 && strings.HasSuffix(entry, "}") {
+		return "@" + entry[k+2:len(entry)-1], true
+	}
+	return "", false
+}
+
+// GetPushedCommits returns the commits a push of localSHA sends to a
+// remote whose ref is at remoteSHA, newest first. When the remote ref is
+// new (all zeros) or its tip is unknown locally, commits already on any
+// of the remote's tracking refs are excluded instead, or on any remote's
+// when remote is a URL rather than a configured remote.
+func GetPushedCommits(remote, localSHA, remoteSHA string) ([]CommitInfo, error) {
+	exclude := []string{"--not", "--remotes"}
+	if isRemote(remote) {
+		exclude = []string{"--not", "--remotes=" + remote}
+	}
+	if strings.Trim(remoteSHA, "0") != "" {
+		if _, err := Run("rev-parse", "--verify", "--quiet", remoteSHA+"^{commit}"); err == nil {
+			exclude = []string{"^" + remoteSHA}
+		}
+	}
+
+	args := append([]string{"log", "--topo-order", "--format=%H %h %s", localSHA}, exclude...)
+	output, err := Run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(output), nil
+}
+
+// isRemote returns true if name is a configured remote
+func isRemote(name string) bool {
+	output, err := Run("remote")
+	if err != nil {
+		return false
+	}
+	for _, remote := range strings.Split(output, "\n") {
+		if remote == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHistory returns the commits reachable from the given commit in
+// topological order, newest first, up to the specified limit (0 = no limit)
+func GetHistory(commit string, limit int) ([]CommitInfo, error) {
+	args := []string{"log", "--topo-order", "--format=%H %h %s"}
+	if limit > 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
 	}
+	args = append(args, commit)
 
 	output, err := Run(args...)
 	if err != nil {
 		return nil, err
 	}
 
+	return parseCommitLog(output), nil
+}
+
+// parseCommitLog parses "<sha> <short-sha> <subject>" lines
+func parseCommitLog(output string) []CommitInfo {
 	if output == "" {
-		return nil, nil
+		return nil
 	}
 
 	lines := strings.Split(output, "\n")
@@ -115,7 +272,7 @@ func GetCommitLog(commit string, limit int) ([]CommitInfo, error) {
 		}
 	}
 
-	return commits, nil
+	return commits
 }
 
 // CommitInfo contains basic information about a commit
@@ -125,3 +282,29 @@ type CommitInfo struct {
 	Subject  string
 }
 
+// ListBranches returns the names of all local branches
+func ListBranches() ([]string, error) {
+	output, err := Run("for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	if err != nil {
+		return nil, err
+	}
+
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// GetBranchCommits returns the set of commits reachable from any local branch
+func GetBranchCommits() (map[string]bool, error) {
+	output, err := Run("rev-list", "--branches")
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make(map[string]bool)
+	for _, sha := range strings.Fields(output) {
+		commits[sha] = true
+	}
+	return commits, nil
+}
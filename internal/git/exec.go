@@ -2,16 +2,32 @@ package git
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
 // NotesRef is the git notes reference used for remarks
 const NotesRef = "remarks"
 
+// SharedNotesRef is the git notes reference that mirrors shared remarks.
+// It is the only remarks ref that is ever pushed.
+const SharedNotesRef = "remarks-shared"
+
 // Run executes a git command and returns the output
 func Run(args ...string) (string, error) {
+	output, err := RunRaw(args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// RunRaw executes a git command and returns the output untrimmed
+func RunRaw(args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
 	cmd.Stdin = nil // Prevent git from waiting for stdin
 	var stdout, stderr bytes.Buffer
@@ -26,7 +42,7 @@ func Run(args ...string) (string, error) {
 		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
 	}
 
-	return strings.TrimSpace(stdout.String()), nil
+	return stdout.String(), nil
 }
 
 // RunWithStdin executes a git command with stdin input
@@ -48,6 +64,16 @@ func RunWithStdin(stdin string, args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// RunInteractive executes a git command attached to the terminal, for
+// commands that may open an editor
+func RunInteractive(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // IsInsideWorkTree checks if the current directory is inside a git repository
 func IsInsideWorkTree() bool {
 	output, err := Run("rev-parse", "--is-inside-work-tree")
@@ -64,3 +90,99 @@ func GetGitDir() (string, error) {
 	return Run("rev-parse", "--git-dir")
 }
 
+// GetHooksDir returns the absolute path of the directory git runs hooks
+// from. This honours core.hooksPath and, in linked worktrees, resolves
+// to the common directory rather than the per-worktree one.
+func GetHooksDir() (string, error) {
+	dir, err := Run("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(dir)
+}
+
+// ErrOutsideRepository is returned for paths outside the work tree
+var ErrOutsideRepository = errors.New("path is outside the repository")
+
+// RepoPath converts a path, absolute or relative to the current
+// directory, into a slash-separated path relative to the repository
+// root. Paths outside the repository are rejected.
+func RepoPath(p string) (string, error) {
+	root, err := GetRepoRoot()
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(p) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		p = filepath.Join(cwd, p)
+	}
+	p = filepath.Clean(p)
+
+	rel, ok := relativeToRoot(root, p)
+	if !ok {
+		return "", ErrOutsideRepository
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// relativeToRoot returns p relative to root. git reports the root with
+// symlinks resolved, so the directory of p that resolves to the root is
+// looked for too. Symlinks below the root are kept, since git tracks
+// them as files.
+func relativeToRoot(root, p string) (string, bool) {
+	if rel, ok := relativeTo(root, p); ok {
+		return rel, true
+	}
+	for dir := filepath.Dir(p); ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil && resolved == root {
+			return relativeTo(dir, p)
+		}
+		if filepath.Dir(dir) == dir {
+			return "", false
+		}
+	}
+}
+
+// relativeTo returns p relative to dir, if p is inside it
+func relativeTo(dir, p string) (string, bool) {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// ErrNotFile is returned for paths that name a directory rather than a file
+var ErrNotFile = errors.New("not a file")
+
+// ReadFileAt returns the content of a file as of the given commit
+func ReadFileAt(commit, filePath string) (string, error) {
+	object := commit + ":" + filePath
+	kind, err := Run("cat-file", "-t", object)
+	if err != nil {
+		return "", err
+	}
+	if kind != "blob" {
+		return "", ErrNotFile
+	}
+	return RunRaw("cat-file", "-p", object)
+}
+
+// GetUserIdent returns the configured user as "Name <email>"
+func GetUserIdent() string {
+	name, _ := Run("config", "user.name")
+	email, _ := Run("config", "user.email")
+
+	switch {
+	case name != "" && email != "":
+		return name + " <" + email + ">"
+	case name != "":
+		return name
+	default:
+		return email
+	}
+}
@@ -0,0 +1,211 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TrackedRange is the location of a line range after tracking it
+// through the changes between two commits
+type TrackedRange struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	Deleted   bool // all lines (or the whole file) were removed
+	Changed   bool // some of the lines were modified
+}
+
+// hunk is a single "@@ -a,b +c,d @@" section of a zero-context diff
+type hunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+}
+
+// fileDiff holds the hunks of one file in a diff
+type fileDiff struct {
+	oldPath, newPath string
+	hunks            []hunk
+}
+
+// TrackLines maps a line range in a file from one commit to another,
+// following renames and line movement the way blame does. A range of
+// 0-0 tracks only the file itself.
+func TrackLines(from, to, path string, start, end int) (*TrackedRange, error) {
+	output, err := RunRaw("diff", "-U0", "-M", "--no-color", "--no-ext-diff", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TrackedRange{Path: path, StartLine: start, EndLine: end}
+
+	var file *fileDiff
+	for _, fd := range parseDiff(output) {
+		if fd.oldPath == path {
+			file = fd
+			break
+		}
+	}
+
+	// File untouched between the two commits
+	if file == nil {
+		return result, nil
+	}
+
+	if file.newPath == "" {
+		result.Deleted = true
+		return result, nil
+	}
+	result.Path = file.newPath
+
+	if start == 0 {
+		return result, nil
+	}
+
+	result.StartLine, result.EndLine = 0, 0
+	for line := start; line <= end; line++ {
+		mapped, changed, ok := mapLine(file.hunks, line)
+		if !ok {
+			continue
+		}
+		if changed {
+			result.Changed = true
+		}
+		if result.StartLine == 0 || mapped < result.StartLine {
+			result.StartLine = mapped
+		}
+		if mapped > result.EndLine {
+			result.EndLine = mapped
+		}
+	}
+
+	if result.StartLine == 0 {
+		result.Deleted = true
+	}
+	return result, nil
+}
+
+// mapLine maps an old line number to the new file. It returns false if
+// the line was deleted, and changed=true if it was modified.
+func mapLine(hunks []hunk, line int) (mapped int, changed bool, ok bool) {
+	offset := 0
+	for _, h := range hunks {
+		// Pure insertion after old line h.oldStart
+		if h.oldCount == 0 {
+			if h.oldStart < line {
+				offset += h.newCount
+				continue
+			}
+			break
+		}
+
+		if line < h.oldStart {
+			break
+		}
+
+		if line < h.oldStart+h.oldCount {
+			if h.newCount == 0 {
+				return 0, false, false
+			}
+			// Modified line: keep its relative position within the hunk
+			rel := line - h.oldStart
+			if rel >= h.newCount {
+				rel = h.newCount - 1
+			}
+			return h.newStart + rel, true, true
+		}
+
+		offset += h.newCount - h.oldCount
+	}
+	return line + offset, false, true
+}
+
+// parseDiff parses the output of a zero-context git diff
+func parseDiff(output string) []*fileDiff {
+	var files []*fileDiff
+	var current *fileDiff
+	inHeader := false
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = &fileDiff{}
+			files = append(files, current)
+			inHeader = true
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "@@ "):
+			inHeader = false
+			if h, err := parseHunkHeader(line); err == nil {
+				current.hunks = append(current.hunks, h)
+			}
+		case !inHeader:
+			continue // A removed "-- comment" line reads as "--- comment"
+		case strings.HasPrefix(line, "rename from "):
+			current.oldPath = unquotePath(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			current.newPath = unquotePath(strings.TrimPrefix(line, "rename to "))
+		case strings.HasPrefix(line, "--- "):
+			current.oldPath = diffPath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			current.newPath = diffPath(strings.TrimPrefix(line, "+++ "), "b/")
+		}
+	}
+
+	return files
+}
+
+// diffPath strips the a/ or b/ prefix from a diff path, and the tab git
+// adds after a path with spaces; /dev/null becomes the empty string
+func diffPath(p, prefix string) string {
+	p = strings.TrimSuffix(p, "\t")
+	if p == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(unquotePath(p), prefix)
+}
+
+// unquotePath decodes a path that git C-quoted because it contains
+// special characters, such as "caf\303\251"
+func unquotePath(p string) string {
+	if !strings.HasPrefix(p, `"`) {
+		return p
+	}
+	if unquoted, err := strconv.Unquote(p); err == nil {
+		return unquoted
+	}
+	return p
+}
+
+// parseHunkHeader parses "@@ -a,b +c,d @@ ..."
+func parseHunkHeader(line string) (hunk, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return hunk{}, fmt.Errorf("invalid hunk header: %s", line)
+	}
+
+	oldStart, oldCount, err := parseHunkRange(strings.TrimPrefix(fields[1], "-"))
+	if err != nil {
+		return hunk{}, err
+	}
+	newStart, newCount, err := parseHunkRange(strings.TrimPrefix(fields[2], "+"))
+	if err != nil {
+		return hunk{}, err
+	}
+
+	return hunk{oldStart: oldStart, oldCount: oldCount, newStart: newStart, newCount: newCount}, nil
+}
+
+// parseHunkRange parses "start,count" where count defaults to 1
+func parseHunkRange(s string) (start, count int, err error) {
+	startStr, countStr, found := strings.Cut(s, ",")
+	start, err = strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return start, 1, nil
+	}
+	count, err = strconv.Atoi(countStr)
+	return start, count, err
+}
@@ -0,0 +1,160 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []fileDiff
+	}{
+		{
+			name: "modified file",
+			output: `diff --git a/main.go b/main.go
+index 9faf6cd..45633b7 100644
+--- a/main.go
++++ b/main.go
+@@ -3,2 +3 @@ package main
+-a
+-b
++c
+@@ -10,0 +10,2 @@ func main() {
++d
++e
+`,
+			want: []fileDiff{{oldPath: "main.go", newPath: "main.go", hunks: []hunk{{3, 2, 3, 1}, {10, 0, 10, 2}}}},
+		},
+		{
+			name: "removed lines that look like file headers",
+			output: `diff --git a/q.sql b/q.sql
+index 9faf6cd..45633b7 100644
+--- a/q.sql
++++ b/q.sql
+@@ -1 +0,0 @@
+--- one
+@@ -4 +3 @@ select 1;
+-++ two
++++ three
+`,
+			want: []fileDiff{{oldPath: "q.sql", newPath: "q.sql", hunks: []hunk{{1, 1, 0, 0}, {4, 1, 3, 1}}}},
+		},
+		{
+			name: "removed line that looks like a rename",
+			output: `diff --git a/notes.txt b/notes.txt
+index 9faf6cd..45633b7 100644
+--- a/notes.txt
++++ b/notes.txt
+@@ -2 +1,0 @@ first
+-rename from here
++rename to there
+`,
+			want: []fileDiff{{oldPath: "notes.txt", newPath: "notes.txt", hunks: []hunk{{2, 1, 1, 0}}}},
+		},
+		{
+			name: "path with spaces",
+			output: "diff --git a/my file.txt b/my file.txt\n" +
+				"index 422c2b7..55dce13 100644\n" +
+				"--- a/my file.txt\t\n" +
+				"+++ b/my file.txt\t\n" +
+				"@@ -2 +2 @@ a\n" +
+				"-b\n" +
+				"+B\n",
+			want: []fileDiff{{oldPath: "my file.txt", newPath: "my file.txt", hunks: []hunk{{2, 1, 2, 1}}}},
+		},
+		{
+			name: "quoted paths in a rename",
+			output: `diff --git "a/caf\303\251.txt" "b/caf\"e.txt"
+similarity index 50%
+rename from "caf\303\251.txt"
+rename to "caf\"e.txt"
+index b77b4eb..206b378 100644
+--- "a/caf\303\251.txt"
++++ "b/caf\"e.txt"
+@@ -2 +2 @@ x
+-y
++z
+`,
+			want: []fileDiff{{oldPath: "café.txt", newPath: `caf"e.txt`, hunks: []hunk{{2, 1, 2, 1}}}},
+		},
+		{
+			name: "rename without changes",
+			output: `diff --git a/old.go b/new.go
+similarity index 100%
+rename from old.go
+rename to new.go
+`,
+			want: []fileDiff{{oldPath: "old.go", newPath: "new.go"}},
+		},
+		{
+			name: "deleted and added files",
+			output: `diff --git a/gone.go b/gone.go
+deleted file mode 100644
+index 9faf6cd..0000000
+--- a/gone.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-a
+-b
+diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..9faf6cd
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1 @@
++a
+`,
+			want: []fileDiff{
+				{oldPath: "gone.go", newPath: "", hunks: []hunk{{1, 2, 0, 0}}},
+				{oldPath: "", newPath: "new.go", hunks: []hunk{{0, 0, 1, 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []fileDiff
+			for _, fd := range parseDiff(tt.output) {
+				got = append(got, *fd)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDiff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapLine(t *testing.T) {
+	hunks := []hunk{
+		{oldStart: 1, oldCount: 1, newStart: 0, newCount: 0}, // line 1 deleted
+		{oldStart: 3, oldCount: 0, newStart: 3, newCount: 2}, // 2 lines inserted after line 3
+		{oldStart: 5, oldCount: 2, newStart: 6, newCount: 1}, // lines 5-6 replaced by one line
+		{oldStart: 9, oldCount: 1, newStart: 9, newCount: 3}, // line 9 replaced by three lines
+	}
+
+	tests := []struct {
+		line    int
+		mapped  int
+		changed bool
+		ok      bool
+	}{
+		{line: 1, ok: false},
+		{line: 2, mapped: 1, ok: true},
+		{line: 3, mapped: 2, ok: true},
+		{line: 4, mapped: 5, ok: true},
+		{line: 5, mapped: 6, changed: true, ok: true},
+		{line: 6, mapped: 6, changed: true, ok: true},
+		{line: 7, mapped: 7, ok: true},
+		{line: 9, mapped: 9, changed: true, ok: true},
+		{line: 10, mapped: 12, ok: true},
+	}
+
+	for _, tt := range tests {
+		mapped, changed, ok := mapLine(hunks, tt.line)
+		if mapped != tt.mapped || changed != tt.changed || ok != tt.ok {
+			t.Errorf("mapLine(%d) = %d, %v, %v, want %d, %v, %v", tt.line, mapped, changed, ok, tt.mapped, tt.changed, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"strings"
+	"time"
+)
+
+// NoteOrigin records who first wrote a note and when
+type NoteOrigin struct {
+	Author string
+	Date   time.Time
+}
+
+// GetNoteOrigins returns, for every object annotated in a notes ref, the
+// author and date of the notes commit that first added its note
+func GetNoteOrigins(ref string) (map[string]NoteOrigin, error) {
+	output, err := Run("log", "--reverse", "--format=%x00%an <%ae>%x00%aI", "--name-only", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	origins := make(map[string]NoteOrigin)
+	var current NoteOrigin
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\x00") {
+			parts := strings.SplitN(strings.TrimPrefix(line, "\x00"), "\x00", 2)
+			current = NoteOrigin{Author: parts[0]}
+			if len(parts) == 2 {
+				current.Date, _ = time.Parse(time.RFC3339, parts[1])
+			}
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Note paths use fanout directories (ab/cdef...)
+		object := strings.ReplaceAll(line, "/", "")
+		if _, seen := origins[object]; !seen {
+			origins[object] = current
+		}
+	}
+
+	return origins, nil
+}
+
+// GetObjectTypes returns the type (commit, blob, tree, tag) of each object
+func GetObjectTypes(objects []string) (map[string]string, error) {
+	if len(objects) == 0 {
+		return map[string]string{}, nil
+	}
+
+	output, err := RunWithStdin(strings.Join(objects, "\n")+"\n", "cat-file", "--batch-check=%(objectname) %(objecttype)")
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			types[fields[0]] = fields[1]
+		}
+	}
+	return types, nil
+}
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 )
 
@@ -55,3 +57,101 @@ func FindCommitByPatchID(patchID, startCommit string, limit int) (string, error)
 	return "", nil
 }
 
+// ErrUnconfirmedRewrite is returned by InferRewrites when the reflog
+// does not show the rewrite it was asked about as the last thing done
+var ErrUnconfirmedRewrite = errors.New("the reflog does not show a just-finished rewrite")
+
+// InferRewrites reconstructs the old → new commit pairs of the last
+// amend or rebase from the reflog, for hook runners that do not pass
+// them on stdin. Rebased commits are matched by patch-id only; old
+// commits without a match are returned as unmatched.
+func InferRewrites(rewriteType string) (pairs [][2]string, unmatched []string, err error) {
+	head, err := GetHEAD()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rewriteType == "amend" {
+		entries, err := headReflog(2)
+		if err != nil || len(entries) < 2 || !strings.HasPrefix(entries[0].Subject, "commit (amend)") {
+			return nil, nil, ErrUnconfirmedRewrite
+		}
+		return [][2]string{{entries[1].SHA, head}}, nil, nil
+	}
+
+	// ORIG_HEAD is also set by reset, merge and pull, so only trust it
+	// when it is the commit HEAD was on before the last rebase started
+	origHead, _ := Run("rev-parse", "--verify", "--quiet", "ORIG_HEAD")
+	if origHead == "" || origHead == head {
+		return nil, nil, nil
+	}
+	if start, err := rebaseStart(); err != nil || start != origHead {
+		return nil, nil, ErrUnconfirmedRewrite
+	}
+
+	oldLog, err := Run("log", "--format=%H %h %s", origHead, "--not", head)
+	if err != nil {
+		return nil, nil, err
+	}
+	newLog, err := Run("log", "--format=%H %h %s", head, "--not", origHead)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byPatchID := make(map[string]string)
+	for _, c := range parseCommitLog(newLog) {
+		if id, err := GetPatchID(c.SHA); err == nil && id != "" {
+			byPatchID[id] = c.SHA
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, c := range parseCommitLog(oldLog) {
+		newSHA := ""
+		if id, err := GetPatchID(c.SHA); err == nil && id != "" {
+			newSHA = byPatchID[id]
+		}
+		if newSHA == "" || used[newSHA] {
+			unmatched = append(unmatched, c.SHA)
+			continue
+		}
+		used[newSHA] = true
+		pairs = append(pairs, [2]string{c.SHA, newSHA})
+	}
+	return pairs, unmatched, nil
+}
+
+// rebaseStart returns the commit HEAD was on before the rebase that the
+// newest HEAD reflog entries belong to
+func rebaseStart() (string, error) {
+	entries, err := headReflog(0)
+	if err != nil {
+		return "", err
+	}
+
+	// Walk back over "rebase (pick)", "rebase -i (finish)", "pull
+	// --rebase (start)" and the like to the entry that started it
+	for i, e := range entries {
+		if !strings.Contains(e.Subject, "rebase") {
+			break
+		}
+		if strings.Contains(e.Subject, "(start)") && i+1 < len(entries) {
+			return entries[i+1].SHA, nil
+		}
+	}
+	return "", ErrUnconfirmedRewrite
+}
+
+// headReflog returns the newest HEAD reflog entries (0 = all), with the
+// reflog message as the subject
+func headReflog(limit int) ([]CommitInfo, error) {
+	args := []string{"log", "-g", "--format=%H %h %gs"}
+	if limit > 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+	output, err := Run(append(args, "HEAD")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(output), nil
+}
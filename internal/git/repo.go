@@ -0,0 +1,124 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRepository is returned when no git repository is found
+var ErrNotRepository = errors.New("not a git repository")
+
+// RepoState is a snapshot of HEAD and a few refs, read straight from the
+// repository files without running git. It is meant for hot paths like
+// shell prompts; anything unusual should fall back to Run.
+type RepoState struct {
+	GitDir    string // per-worktree git directory
+	CommonDir string // shared git directory (same as GitDir outside worktrees)
+	Branch    string // empty when HEAD is detached
+	HEAD      string // empty on an unborn branch
+}
+
+// ReadRepoState locates the repository containing dir and reads HEAD
+func ReadRepoState(dir string) (*RepoState, error) {
+	gitDir, err := findGitDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &RepoState{GitDir: gitDir, CommonDir: gitDir}
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		state.CommonDir = filepath.Clean(common)
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return nil, ErrNotRepository
+	}
+	head := strings.TrimSpace(string(data))
+
+	if ref, ok := strings.CutPrefix(head, "ref: "); ok {
+		state.Branch = strings.TrimPrefix(ref, "refs/heads/")
+		state.HEAD = state.ReadRef(ref)
+	} else {
+		state.HEAD = head
+	}
+
+	return state, nil
+}
+
+// ReadRef resolves a full ref name from loose refs or packed-refs.
+// It returns an empty string if the ref does not exist.
+func (s *RepoState) ReadRef(ref string) string {
+	for _, dir := range []string{s.GitDir, s.CommonDir} {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref)))
+		if err != nil {
+			continue
+		}
+		value := strings.TrimSpace(string(data))
+		if target, ok := strings.CutPrefix(value, "ref: "); ok {
+			return s.ReadRef(target)
+		}
+		return value
+	}
+
+	f, err := os.Open(filepath.Join(s.CommonDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if sha, name, ok := strings.Cut(line, " "); ok && name == ref {
+			return sha
+		}
+	}
+	return ""
+}
+
+// findGitDir walks up from dir looking for a .git directory or file
+func findGitDir(dir string) (string, error) {
+	if env := os.Getenv("GIT_DIR"); env != "" {
+		return filepath.Abs(env)
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, ".git")
+		info, err := os.Stat(candidate)
+		if err == nil {
+			if info.IsDir() {
+				return candidate, nil
+			}
+			// Linked worktrees and submodules use a "gitdir: <path>" file
+			data, err := os.ReadFile(candidate)
+			if err != nil {
+				return "", err
+			}
+			if target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: "); ok {
+				if !filepath.IsAbs(target) {
+					target = filepath.Join(dir, target)
+				}
+				return filepath.Clean(target), nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNotRepository
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,278 @@
+package hook
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers delimit the block git-remarks manages inside a hook script.
+// Everything outside the markers belongs to the user or another tool.
+const (
+	BeginMarker = "# >>> git-remarks >>>"
+	EndMarker   = "# <<< git-remarks <<<"
+)
+
+// PostRewrite is the script run by the post-rewrite hook
+const PostRewrite = `# Migrates remarks when commits are rewritten (rebase, amend)
+if command -v git-remarks >/dev/null 2>&1; then
+    git-remarks migrate-rewrites "$1"
+fi`
+
+// PrePush is the script run by the pre-push hook. git-remarks reads the
+// pushed refs from the hook's stdin.
+const PrePush = `# Checks pushed commits for unresolved todo and doubt remarks
+if command -v git-remarks >/dev/null 2>&1; then
+    git-remarks pre-push "$1" || exit 1
+fi`
+
+// CommitMsg is the script run by the commit-msg hook. It moves
+// "Remark(type): ..." lines out of the message for PostCommit.
+const CommitMsg = `# Takes remarks written in the commit message out of it
+if command -v git-remarks >/dev/null 2>&1; then
+    git-remarks commit-msg "$1" || exit 1
+fi`
+
+// PostCommit is the script run by the post-commit hook
+const PostCommit = `# Attaches remarks taken from the commit message to the new commit
+if command -v git-remarks >/dev/null 2>&1; then
+    git-remarks post-commit
+fi`
+
+// legacyScript is the whole post-rewrite hook init wrote before markers
+// were introduced. Only an unmodified copy is treated as ours.
+const legacyScript = `#!/bin/sh
+# git-remarks post-rewrite hook
+# Migrates remarks when commits are rewritten (rebase, amend)
+
+rewrite_type="$1"
+
+# Check if git-remarks is available
+if ! command -v git-remarks >/dev/null 2>&1; then
+    exit 0
+fi
+
+# Pass stdin to git-remarks migrate-rewrites
+git-remarks migrate-rewrites "$rewrite_type"
+`
+
+// legacySnippet is what init used to append to an existing hook
+const legacySnippet = `
+# git-remarks hook (appended)
+if command -v git-remarks >/dev/null 2>&1; then
+    git-remarks migrate-rewrites "$1"
+fi
+`
+
+// Status describes how a hook script relates to git-remarks
+type Status int
+
+const (
+	// StatusMissing means the hook file does not exist
+	StatusMissing Status = iota
+	// StatusAbsent means the hook exists without a git-remarks block
+	StatusAbsent
+	// StatusCurrent means the hook has an up-to-date block
+	StatusCurrent
+	// StatusOutdated means the hook has a block with a different script
+	StatusOutdated
+	// StatusLegacy means the hook was installed before markers existed
+	StatusLegacy
+	// StatusManual means the hook calls git-remarks outside a block,
+	// so it was written by hand and is left alone
+	StatusManual
+)
+
+// Result is the outcome of Install or Uninstall
+type Result int
+
+const (
+	Installed Result = iota
+	Appended
+	Upgraded
+	Unchanged
+	NeedsUpgrade
+	Removed
+	NotInstalled
+	Manual
+	// NotAppended means the hook exists and the script was not added to
+	// it, because it could not run after the hook's own commands
+	NotAppended
+)
+
+// Block returns the marked block that runs script
+func Block(script string) string {
+	return BeginMarker + "\n" + strings.TrimRight(script, "\n") + "\n" + EndMarker + "\n"
+}
+
+// Inspect reports the status of hook content against the script that
+// should be installed
+func Inspect(content, script string) Status {
+	if start, end, ok := findBlock(content); ok {
+		if content[start:end] == Block(script) {
+			return StatusCurrent
+		}
+		return StatusOutdated
+	}
+
+	if isLegacyHook(content) || strings.Contains(content, legacySnippet) {
+		return StatusLegacy
+	}
+
+	if strings.Contains(content, "git-remarks") || strings.Contains(content, "git remarks") {
+		return StatusManual
+	}
+	return StatusAbsent
+}
+
+// InspectFile reports the status of the hook at path
+func InspectFile(path, script string) (Status, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return StatusMissing, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read hook: %w", err)
+	}
+	return Inspect(string(content), script), nil
+}
+
+// Install adds the block running script to the hook at path, creating
+// the hook if needed. An outdated or legacy block is only replaced when
+// upgrade is set.
+func Install(path, script string, upgrade bool) (Result, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return 0, fmt.Errorf("failed to create hooks directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n\n"+Block(script)), 0755); err != nil {
+			return 0, fmt.Errorf("failed to write hook: %w", err)
+		}
+		return Installed, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read hook: %w", err)
+	}
+
+	text := string(content)
+	switch Inspect(text, script) {
+	case StatusCurrent:
+		return Unchanged, nil
+	case StatusManual:
+		return Manual, nil
+	case StatusAbsent:
+		if !appendable(script) {
+			return NotAppended, nil
+		}
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		return Appended, writeHook(path, text+"\n"+Block(script))
+	}
+
+	if !upgrade {
+		return NeedsUpgrade, nil
+	}
+
+	switch {
+	case isLegacyHook(text):
+		text = "#!/bin/sh\n\n" + Block(script)
+	case strings.Contains(text, legacySnippet):
+		text = strings.Replace(text, legacySnippet, "\n"+Block(script), 1)
+	default:
+		start, end, _ := findBlock(text)
+		text = text[:start] + Block(script) + text[end:]
+	}
+	return Upgraded, writeHook(path, text)
+}
+
+// Uninstall removes the git-remarks block from the hook at path, and
+// the hook itself if nothing else is left in it
+func Uninstall(path string) (Result, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NotInstalled, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read hook: %w", err)
+	}
+
+	text := string(content)
+	switch {
+	case isLegacyHook(text):
+		text = ""
+	case strings.Contains(text, legacySnippet):
+		text = strings.Replace(text, legacySnippet, "", 1)
+	default:
+		start, end, ok := findBlock(text)
+		if !ok {
+			if Inspect(text, "") == StatusManual {
+				return Manual, nil
+			}
+			return NotInstalled, nil
+		}
+		// Drop the blank line Install adds before the block
+		if strings.HasSuffix(text[:start], "\n\n") {
+			start--
+		}
+		text = text[:start] + text[end:]
+	}
+
+	if rest := strings.TrimSpace(text); rest == "" || rest == "#!/bin/sh" {
+		if err := os.Remove(path); err != nil {
+			return 0, fmt.Errorf("failed to remove hook: %w", err)
+		}
+		return Removed, nil
+	}
+	return Removed, writeHook(path, text)
+}
+
+// findBlock returns the byte range of the marked block, including the
+// markers and the trailing newline
+func findBlock(content string) (int, int, bool) {
+	start := strings.Index(content, BeginMarker)
+	if start < 0 {
+		return 0, 0, false
+	}
+	if start > 0 && content[start-1] != '\n' {
+		return 0, 0, false
+	}
+
+	rel := strings.Index(content[start:], EndMarker)
+	if rel < 0 {
+		return 0, 0, false
+	}
+	end := start + rel + len(EndMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return start, end, true
+}
+
+// appendable reports whether script can run after other commands in a
+// hook. PrePush reads the pushed refs from stdin, which an earlier
+// command such as 'git lfs pre-push' may already have consumed.
+func appendable(script string) bool {
+	return script != PrePush
+}
+
+// isLegacyHook returns true if the whole hook was written by init
+// before markers were introduced and has not been edited since. An
+// edited copy is reported as manual, so user changes are never lost.
+func isLegacyHook(content string) bool {
+	return content == legacyScript
+}
+
+// writeHook rewrites a hook, keeping its permissions
+func writeHook(path, content string) error {
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		return fmt.Errorf("failed to write hook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,580 @@
+package hook
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manager is a tool that owns the repository's git hooks
+type Manager string
+
+const (
+	ManagerLefthook  Manager = "lefthook"
+	ManagerPreCommit Manager = "pre-commit"
+	ManagerHusky     Manager = "husky"
+)
+
+// Managers lists the supported hook managers in detection order
+var Managers = []Manager{ManagerLefthook, ManagerPreCommit, ManagerHusky}
+
+// StepID names the git-remarks entry in hook manager configs
+const StepID = "git-remarks"
+
+// lefthookRun is the lefthook command; {1} is the rewrite type and
+// use_stdin forwards the rewritten commit pairs
+const lefthookRun = "git-remarks migrate-rewrites {1}"
+
+// preCommitEntry is the pre-commit entry. pre-commit does not forward
+// the hook's stdin, so the rewrites are inferred from the reflog.
+const preCommitEntry = "git-remarks migrate-rewrites --infer"
+
+// configFiles lists the config files of each manager, preferred first
+var configFiles = map[Manager][]string{
+	ManagerLefthook:  {"lefthook.yml", ".lefthook.yml", "lefthook.yaml", ".lefthook.yaml", ".config/lefthook.yml", ".config/lefthook.yaml"},
+	ManagerPreCommit: {".pre-commit-config.yaml", ".pre-commit-config.yml"},
+	ManagerHusky:     {".husky"},
+}
+
+// ParseManager validates a manager name
+func ParseManager(name string) (Manager, error) {
+	for _, m := range Managers {
+		if string(m) == name {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown hook manager: %s (must be lefthook, pre-commit, or husky)", name)
+}
+
+// DetectManager returns the first hook manager whose config exists in root
+func DetectManager(root string) (Manager, bool) {
+	for _, m := range Managers {
+		if _, ok := existingConfig(root, m); ok {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// ConfigPath returns the config file (or, for husky, the hook script)
+// that holds the git-remarks step
+func ConfigPath(root string, m Manager) string {
+	path, ok := existingConfig(root, m)
+	if !ok {
+		path = filepath.Join(root, configFiles[m][0])
+	}
+	if m == ManagerHusky {
+		return filepath.Join(path, "post-rewrite")
+	}
+	return path
+}
+
+// InstallCommand is the command that makes the manager write its hooks
+func InstallCommand(m Manager) string {
+	switch m {
+	case ManagerLefthook:
+		return "lefthook install"
+	case ManagerPreCommit:
+		return "pre-commit install --hook-type post-rewrite"
+	default:
+		return "npx husky"
+	}
+}
+
+// InstallManager adds the migrate-rewrites step to the manager's config
+// in root, keeping everything else in it
+func InstallManager(root string, m Manager, upgrade bool) (Result, error) {
+	path := ConfigPath(root, m)
+	if m == ManagerHusky {
+		return Install(path, PostRewrite, upgrade)
+	}
+
+	lines, doc, err := readYAML(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var result Result
+	var edits []configEdit
+	switch m {
+	case ManagerLefthook:
+		result, edits, err = lefthookEdits(doc, lines, upgrade)
+	case ManagerPreCommit:
+		result, edits, err = preCommitEdits(doc, lines, upgrade)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot add the git-remarks step to %s: %w; add it by hand", path, err)
+	}
+	if result != Installed && result != Upgraded {
+		return result, nil
+	}
+
+	if err := writeConfig(path, m, applyEdits(lines, edits)); err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// HasStep reports whether the manager's config runs migrate-rewrites
+func HasStep(root string, m Manager) (bool, error) {
+	path := ConfigPath(root, m)
+	if m == ManagerHusky {
+		status, err := InspectFile(path, PostRewrite)
+		if err != nil {
+			return false, err
+		}
+		return status == StatusCurrent || status == StatusOutdated || status == StatusLegacy, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return strings.Contains(string(content), "git-remarks migrate-rewrites"), nil
+}
+
+// RunsHooks reports whether the manager has installed the post-rewrite
+// hook git runs from hooksDir
+func RunsHooks(root, hooksDir string, m Manager) bool {
+	content, err := os.ReadFile(filepath.Join(hooksDir, "post-rewrite"))
+	if err != nil {
+		return false
+	}
+
+	if m == ManagerHusky {
+		// husky points core.hooksPath into .husky
+		rel, err := filepath.Rel(root, hooksDir)
+		return err == nil && (rel == ".husky" || strings.HasPrefix(rel, ".husky"+string(filepath.Separator)))
+	}
+	return strings.Contains(string(content), string(m))
+}
+
+func existingConfig(root string, m Manager) (string, bool) {
+	for _, name := range configFiles[m] {
+		path := filepath.Join(root, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// configEdit replaces remove lines of a config file, starting at the
+// 0-based line start, with new lines. The file is edited as text so its
+// indentation, quoting and comments are kept.
+type configEdit struct {
+	start  int
+	remove int
+	lines  []string
+}
+
+// lefthookStep returns the post-rewrite.commands.git-remarks entry, and
+// its lines indented by unit
+func lefthookStep(unit int) (*yaml.Node, []string) {
+	step := &yaml.Node{Kind: yaml.MappingNode}
+	setScalar(step, "run", lefthookRun)
+	setValue(step, "use_stdin", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+
+	return step, []string{
+		StepID + ":",
+		spaces(unit) + "run: " + lefthookRun,
+		spaces(unit) + "use_stdin: true",
+	}
+}
+
+// preCommitStep returns the git-remarks hook of a local repo, and its
+// lines as they follow the "- " of a list item
+func preCommitStep() (*yaml.Node, []string) {
+	step := &yaml.Node{Kind: yaml.MappingNode}
+	setScalar(step, "id", StepID)
+	setScalar(step, "name", "git-remarks migrate-rewrites")
+	setScalar(step, "entry", preCommitEntry)
+	setScalar(step, "language", "system")
+	setValue(step, "stages", flowSequence("post-rewrite"))
+	setValue(step, "always_run", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+	setValue(step, "pass_filenames", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"})
+
+	return step, []string{
+		"id: " + StepID,
+		"name: git-remarks migrate-rewrites",
+		"entry: " + preCommitEntry,
+		"language: system",
+		"stages: [post-rewrite]",
+		"always_run: true",
+		"pass_filenames: false",
+	}
+}
+
+// lefthookEdits adds post-rewrite.commands.git-remarks
+func lefthookEdits(doc *yaml.Node, lines []string, upgrade bool) (Result, []configEdit, error) {
+	root := doc.Content[0]
+	unit := indentUnit(root)
+	step, stepLines := lefthookStep(unit)
+
+	hookKey, hook := entryOf(root, "post-rewrite")
+	if hook == nil {
+		added := append([]string{"post-rewrite:", spaces(unit) + "commands:"}, indent(2*unit, stepLines)...)
+		return Installed, []configEdit{appendEdit(lines, added)}, nil
+	}
+	if !isBlockMapping(hook) {
+		return 0, nil, fmt.Errorf("post-rewrite is not a block mapping")
+	}
+
+	commandsKey, commands := entryOf(hook, "commands")
+	if commands == nil {
+		col := hook.Content[0].Column - 1
+		added := append([]string{"commands:"}, indent(unit, stepLines)...)
+		return Installed, []configEdit{{start: hookKey.Line, lines: indent(col, added)}}, nil
+	}
+	if !isBlockMapping(commands) {
+		return 0, nil, fmt.Errorf("post-rewrite.commands is not a block mapping")
+	}
+
+	key, existing := entryOf(commands, StepID)
+	switch {
+	case existing == nil:
+		col := commands.Content[0].Column - 1
+		return Installed, []configEdit{{start: commandsKey.Line, lines: indent(col, stepLines)}}, nil
+	case sameNode(existing, step):
+		return Unchanged, nil, nil
+	case !upgrade:
+		return NeedsUpgrade, nil, nil
+	default:
+		return Upgraded, []configEdit{{
+			start:  key.Line - 1,
+			remove: lastLine(existing) - key.Line + 1,
+			lines:  indent(key.Column-1, stepLines),
+		}}, nil
+	}
+}
+
+// preCommitEdits adds a local repo hook with the git-remarks id and
+// enables the post-rewrite hook type
+func preCommitEdits(doc *yaml.Node, lines []string, upgrade bool) (Result, []configEdit, error) {
+	root := doc.Content[0]
+	step, stepLines := preCommitStep()
+
+	var edits []configEdit
+	result := Installed
+
+	reposKey, repos := entryOf(root, "repos")
+	existing, index := findPreCommitStep(repos)
+	switch {
+	case repos == nil:
+		edits = append(edits, appendEdit(lines, append([]string{"repos:"}, localRepo("- ", 0, stepLines)...)))
+	case existing == nil:
+		if !isBlockSequence(repos) {
+			return 0, nil, fmt.Errorf("repos is not a block list")
+		}
+		// Follow the layout of the first repo
+		prefix := itemPrefix(lines, repos.Content[0])
+		dash := len(prefix) - len(strings.TrimLeft(prefix, " "))
+		edits = append(edits, configEdit{start: reposKey.Line, lines: localRepo(prefix, dash-(reposKey.Column-1), stepLines)})
+	case sameNode(existing.Content[index], step):
+		result = Unchanged
+	case !upgrade:
+		return NeedsUpgrade, nil, nil
+	default:
+		item := existing.Content[index]
+		edits = append(edits, configEdit{
+			start:  item.Line - 1,
+			remove: lastLine(item) - item.Line + 1,
+			lines:  listItem(itemPrefix(lines, item), stepLines),
+		})
+		result = Upgraded
+	}
+
+	// pre-commit only installs the hook types listed here (default: pre-commit)
+	typesKey, types := entryOf(root, "default_install_hook_types")
+	if types == nil {
+		edits = append(edits, appendEdit(lines, []string{"default_install_hook_types: [pre-commit, post-rewrite]"}))
+		return changed(result), edits, nil
+	}
+	if types.Kind != yaml.SequenceNode {
+		return 0, nil, fmt.Errorf("default_install_hook_types is not a list")
+	}
+	for _, t := range types.Content {
+		if t.Value == "post-rewrite" {
+			return result, edits, nil
+		}
+	}
+
+	if isBlockSequence(types) {
+		prefix := itemPrefix(lines, types.Content[0])
+		edits = append(edits, configEdit{start: typesKey.Line, lines: []string{prefix + "post-rewrite"}})
+		return changed(result), edits, nil
+	}
+
+	// A flow list: add the type before its closing bracket
+	line := lines[types.Line-1]
+	end := strings.Index(line[types.Column-1:], "]")
+	if end < 0 {
+		return 0, nil, fmt.Errorf("default_install_hook_types spans several lines")
+	}
+	end += types.Column - 1
+	value := "post-rewrite"
+	if len(types.Content) > 0 {
+		value = ", " + value
+	}
+	edits = append(edits, configEdit{start: types.Line - 1, remove: 1, lines: []string{line[:end] + value + line[end:]}})
+	return changed(result), edits, nil
+}
+
+// localRepo returns the lines of a "repo: local" list item holding the
+// git-remarks hook. prefix is the item's indentation and dash, and
+// seqIndent how far list items are indented from their key.
+func localRepo(prefix string, seqIndent int, stepLines []string) []string {
+	keys := spaces(len(prefix))
+	hookPrefix := keys + spaces(seqIndent) + "- "
+	return append(listItem(prefix, []string{"repo: local", "hooks:"}), listItem(hookPrefix, stepLines)...)
+}
+
+// findPreCommitStep returns the hooks sequence holding the git-remarks
+// hook and its index
+func findPreCommitStep(repos *yaml.Node) (*yaml.Node, int) {
+	if repos == nil {
+		return nil, 0
+	}
+	for _, repo := range repos.Content {
+		hooks := valueOf(repo, "hooks")
+		if hooks == nil || hooks.Kind != yaml.SequenceNode {
+			continue
+		}
+		for i, h := range hooks.Content {
+			if id := valueOf(h, "id"); id != nil && id.Value == StepID {
+				return hooks, i
+			}
+		}
+	}
+	return nil, 0
+}
+
+// changed turns Unchanged into Installed after a config edit
+func changed(result Result) Result {
+	if result == Unchanged {
+		return Installed
+	}
+	return result
+}
+
+// entryOf returns the key and value nodes for key in a mapping node
+func entryOf(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// valueOf returns the value node for key in a mapping node
+func valueOf(mapping *yaml.Node, key string) *yaml.Node {
+	_, value := entryOf(mapping, key)
+	return value
+}
+
+// setValue sets or replaces key in a mapping node
+func setValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		value,
+	)
+}
+
+func setScalar(mapping *yaml.Node, key, value string) {
+	setValue(mapping, key, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
+
+func flowSequence(values ...string) *yaml.Node {
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, v := range values {
+		seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v})
+	}
+	return seq
+}
+
+// sameNode compares two nodes by their decoded values
+func sameNode(a, b *yaml.Node) bool {
+	var va, vb any
+	if a.Decode(&va) != nil || b.Decode(&vb) != nil {
+		return false
+	}
+	return fmt.Sprint(va) == fmt.Sprint(vb)
+}
+
+func isBlockMapping(n *yaml.Node) bool {
+	return n.Kind == yaml.MappingNode && n.Style&yaml.FlowStyle == 0 && len(n.Content) > 0
+}
+
+func isBlockSequence(n *yaml.Node) bool {
+	return n.Kind == yaml.SequenceNode && n.Style&yaml.FlowStyle == 0 && len(n.Content) > 0
+}
+
+// indentUnit returns the indentation step of a document, taken from its
+// first nested block mapping, or 2
+func indentUnit(root *yaml.Node) int {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if isBlockMapping(value) && value.Content[0].Column > key.Column {
+			return value.Content[0].Column - key.Column
+		}
+	}
+	return 2
+}
+
+// lastLine returns the last line a node's content starts on. Multi-line
+// scalars are not followed, which is enough for the entries git-remarks
+// writes.
+func lastLine(n *yaml.Node) int {
+	last := n.Line
+	for _, child := range n.Content {
+		if l := lastLine(child); l > last {
+			last = l
+		}
+	}
+	return last
+}
+
+// itemPrefix returns the indentation and dash before a list item
+func itemPrefix(lines []string, item *yaml.Node) string {
+	return lines[item.Line-1][:item.Column-1]
+}
+
+// listItem returns body as a list item: the first line after prefix and
+// the others aligned with it
+func listItem(prefix string, body []string) []string {
+	result := []string{prefix + body[0]}
+	return append(result, indent(len(prefix), body[1:])...)
+}
+
+func indent(n int, lines []string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = spaces(n) + line
+	}
+	return result
+}
+
+func spaces(n int) string {
+	return strings.Repeat(" ", n)
+}
+
+// appendEdit adds lines at the end of a config file. Top-level keys can
+// always go there: nothing indented is left open at column zero.
+func appendEdit(lines []string, added []string) configEdit {
+	return configEdit{start: len(lines), lines: added}
+}
+
+// applyEdits returns lines with the edits applied
+func applyEdits(lines []string, edits []configEdit) []string {
+	// Apply bottom-up so line numbers stay valid, and edits at the same
+	// line last first so they keep their order
+	order := make([]int, len(edits))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		ea, eb := edits[order[a]], edits[order[b]]
+		if ea.start != eb.start {
+			return ea.start > eb.start
+		}
+		return order[a] > order[b]
+	})
+
+	result := append([]string(nil), lines...)
+	for _, i := range order {
+		e := edits[i]
+		tail := append(append([]string(nil), e.lines...), result[e.start+e.remove:]...)
+		result = append(result[:e.start], tail...)
+	}
+	return result
+}
+
+// readYAML reads a config file as lines and parses it into a document
+// node, starting an empty mapping if the file does not exist
+func readYAML(path string) ([]string, *yaml.Node, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var lines []string
+	if len(content) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	}
+
+	doc := &yaml.Node{}
+	if len(bytes.TrimSpace(content)) > 0 {
+		if err := yaml.Unmarshal(content, doc); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("failed to parse %s: top level is not a mapping", path)
+	}
+	return lines, doc, nil
+}
+
+// writeConfig writes an edited config after checking that it still
+// parses and holds the git-remarks step
+func writeConfig(path string, m Manager, lines []string) error {
+	content := strings.Join(lines, "\n") + "\n"
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(content), doc); err != nil || !hasCurrentStep(doc, m) {
+		return fmt.Errorf("cannot add the git-remarks step to %s without breaking it; add it by hand", path)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// hasCurrentStep reports whether a parsed config holds the current
+// git-remarks step
+func hasCurrentStep(doc *yaml.Node, m Manager) bool {
+	if len(doc.Content) == 0 {
+		return false
+	}
+	root := doc.Content[0]
+
+	switch m {
+	case ManagerLefthook:
+		existing := valueOf(valueOf(valueOf(root, "post-rewrite"), "commands"), StepID)
+		step, _ := lefthookStep(0)
+		return existing != nil && sameNode(existing, step)
+	case ManagerPreCommit:
+		hooks, index := findPreCommitStep(valueOf(root, "repos"))
+		step, _ := preCommitStep()
+		return hooks != nil && sameNode(hooks.Content[index], step)
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package remark
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportVersion is the version of the export document format
+const ExportVersion = 1
+
+// Entry is a remark together with the commit it is attached to, as
+// used by export and import
+type Entry struct {
+	Commit string `yaml:"commit" json:"commit"`
+	Remark `yaml:",inline"`
+}
+
+// ExportDocument is the JSON/YAML export format
+type ExportDocument struct {
+	Version int     `yaml:"version" json:"version"`
+	Remarks []Entry `yaml:"remarks" json:"remarks"`
+}
+
+// CSVHeader is the header row of the CSV export format
+var CSVHeader = []string{"commit", "id", "type", "branch", "state", "created_at", "author", "path", "start_line", "end_line", "body"}
+
+// CSVRecord converts an entry to a CSV row matching CSVHeader
+func (e Entry) CSVRecord() []string {
+	var path, start, end string
+	if e.Anchor != nil {
+		path = e.Anchor.Path
+		if e.Anchor.HasLines() {
+			start = strconv.Itoa(e.Anchor.StartLine)
+			end = strconv.Itoa(e.Anchor.EndLine)
+		}
+	}
+
+	return []string{
+		e.Commit,
+		e.ID,
+		string(e.Type),
+		e.Branch,
+		string(e.State),
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.Author,
+		path,
+		start,
+		end,
+		e.Body,
+	}
+}
+
+// EntryFromCSV converts a CSV row to an entry using the column names
+// in header. Unknown columns are ignored.
+func EntryFromCSV(header, record []string) (Entry, error) {
+	var e Entry
+	var startLine, endLine int
+
+	for i, column := range header {
+		if i >= len(record) {
+			break
+		}
+		value := record[i]
+		column = strings.TrimSpace(strings.ToLower(column))
+
+		switch column {
+		case "commit":
+			e.Commit = value
+		case "id":
+			e.ID = value
+		case "type":
+			e.Type = Type(value)
+		case "branch":
+			e.Branch = value
+		case "state":
+			e.State = State(value)
+		case "created_at":
+			if value == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return e, fmt.Errorf("invalid created_at: %s", value)
+			}
+			e.CreatedAt = t
+		case "author":
+			e.Author = value
+		case "path":
+			if value != "" {
+				e.Anchor = &Anchor{Path: value}
+			}
+		case "start_line", "end_line":
+			if value == "" {
+				continue
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return e, fmt.Errorf("invalid %s: %s", column, value)
+			}
+			if column == "start_line" {
+				startLine = n
+			} else {
+				endLine = n
+			}
+		case "body":
+			e.Body = value
+		}
+	}
+
+	if e.Anchor != nil {
+		e.Anchor.StartLine = startLine
+		e.Anchor.EndLine = endLine
+	}
+
+	return e, nil
+}
@@ -0,0 +1,175 @@
+package remark
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Notes maps annotated commits to their remarks, as stored in a notes ref
+type Notes map[string]*Remarks
+
+// Conflict is a field of a remark changed differently on both sides of
+// a merge
+type Conflict struct {
+	ID    string
+	Field string
+	Ours  any
+	Their any
+}
+
+// located is a remark together with the commit it is attached to
+type located struct {
+	Commit string
+	Remark Remark
+}
+
+// mergeField reads and writes one mergeable field of a located remark
+type mergeField struct {
+	Name string
+	Get  func(l *located) any
+	Set  func(dst, src *located)
+}
+
+var mergeFields = []mergeField{
+	{"commit", func(l *located) any { return l.Commit }, func(d, s *located) { d.Commit = s.Commit }},
+	{"type", func(l *located) any { return l.Remark.Type }, func(d, s *located) { d.Remark.Type = s.Remark.Type }},
+	{"branch", func(l *located) any { return l.Remark.Branch }, func(d, s *located) { d.Remark.Branch = s.Remark.Branch }},
+	{"body", func(l *located) any { return l.Remark.Body }, func(d, s *located) { d.Remark.Body = s.Remark.Body }},
+	{"author", func(l *located) any { return l.Remark.Author }, func(d, s *located) { d.Remark.Author = s.Remark.Author }},
+	{"anchor", func(l *located) any { return l.Remark.Anchor }, func(d, s *located) { d.Remark.Anchor = s.Remark.Anchor }},
+	{"copied_from", func(l *located) any { return l.Remark.CopiedFrom }, func(d, s *located) { d.Remark.CopiedFrom = s.Remark.CopiedFrom }},
+	{"created_at", func(l *located) any { return l.Remark.CreatedAt }, func(d, s *located) { d.Remark.CreatedAt = s.Remark.CreatedAt }},
+	{"visibility", func(l *located) any { return l.Remark.Visibility }, func(d, s *located) { d.Remark.Visibility = s.Remark.Visibility }},
+}
+
+// MergeNotes merges two versions of a notes ref given their common base
+// (which may be nil). Remarks are matched by ID wherever they are
+// attached:
+//
+//   - a remark on one side only is kept, unless it is in the base and
+//     was left unchanged by the side that removed it
+//   - each field changed on one side only takes that side's value
+//   - a field changed on both sides takes the value of the side modified
+//     last, and is reported as a conflict
+//   - a remark resolved on either side stays resolved
+func MergeNotes(base, ours, theirs Notes) (Notes, []Conflict) {
+	baseIndex := indexNotes(base)
+	ourIndex := indexNotes(ours)
+	theirIndex := indexNotes(theirs)
+
+	merged := make(Notes)
+	var conflicts []Conflict
+
+	for _, id := range mergeOrder(ours, theirs) {
+		b, inBase := baseIndex[id]
+		o, inOurs := ourIndex[id]
+		t, inTheirs := theirIndex[id]
+
+		var result located
+		switch {
+		case inOurs && inTheirs:
+			var fieldConflicts []Conflict
+			result, fieldConflicts = mergeRemark(b, o, t, inBase)
+			conflicts = append(conflicts, fieldConflicts...)
+		case inOurs:
+			// Removed by them: drop it unless we changed it since
+			if inBase && reflect.DeepEqual(b, o) {
+				continue
+			}
+			result = o
+		default:
+			if inBase && reflect.DeepEqual(b, t) {
+				continue
+			}
+			result = t
+		}
+
+		if merged[result.Commit] == nil {
+			merged[result.Commit] = &Remarks{}
+		}
+		merged[result.Commit].Add(result.Remark)
+	}
+
+	return merged, conflicts
+}
+
+// mergeRemark merges one remark present on both sides
+func mergeRemark(base, ours, theirs located, inBase bool) (located, []Conflict) {
+	if reflect.DeepEqual(ours, theirs) {
+		return ours, nil
+	}
+
+	theirsNewer := theirs.Remark.LastModified().After(ours.Remark.LastModified())
+	result := ours
+	var conflicts []Conflict
+
+	for _, f := range mergeFields {
+		o, t := f.Get(&ours), f.Get(&theirs)
+		if reflect.DeepEqual(o, t) {
+			continue
+		}
+
+		switch {
+		case inBase && reflect.DeepEqual(f.Get(&base), o):
+			// Only they changed it
+			f.Set(&result, &theirs)
+		case inBase && reflect.DeepEqual(f.Get(&base), t):
+			// Only we changed it
+		default:
+			conflicts = append(conflicts, Conflict{ID: ours.Remark.ID, Field: f.Name, Ours: o, Their: t})
+			if theirsNewer {
+				f.Set(&result, &theirs)
+			}
+		}
+	}
+
+	if ours.Remark.State == StateResolved || theirs.Remark.State == StateResolved {
+		result.Remark.State = StateResolved
+	}
+
+	if theirsNewer {
+		result.Remark.UpdatedAt = theirs.Remark.UpdatedAt
+	}
+	return result, conflicts
+}
+
+// indexNotes maps remark IDs to their location
+func indexNotes(notes Notes) map[string]located {
+	index := make(map[string]located)
+	for commit, remarks := range notes {
+		if remarks == nil {
+			continue
+		}
+		for _, r := range remarks.Remarks {
+			index[r.ID] = located{Commit: commit, Remark: r}
+		}
+	}
+	return index
+}
+
+// mergeOrder lists remark IDs in a stable order: ours as stored, then
+// theirs, with commits sorted
+func mergeOrder(ours, theirs Notes) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, notes := range []Notes{ours, theirs} {
+		commits := make([]string, 0, len(notes))
+		for commit := range notes {
+			commits = append(commits, commit)
+		}
+		sort.Strings(commits)
+
+		for _, commit := range commits {
+			if notes[commit] == nil {
+				continue
+			}
+			for _, r := range notes[commit].Remarks {
+				if !seen[r.ID] {
+					seen[r.ID] = true
+					ids = append(ids, r.ID)
+				}
+			}
+		}
+	}
+	return ids
+}
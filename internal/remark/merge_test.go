@@ -0,0 +1,198 @@
+package remark
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	created = time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	earlier = created.Add(time.Hour)
+	later   = created.Add(2 * time.Hour)
+)
+
+// baseRemark returns the base version of a remark, edited by edits
+func baseRemark(id string, edits ...func(*Remark)) Remark {
+	r := Remark{
+		ID:        id,
+		Type:      TypeThought,
+		Branch:    "main",
+		State:     StateActive,
+		CreatedAt: created,
+		Body:      "Body of " + id,
+	}
+	for _, edit := range edits {
+		edit(&r)
+	}
+	return r
+}
+
+func withBody(body string, at time.Time) func(*Remark) {
+	return func(r *Remark) {
+		r.Body = body
+		r.UpdatedAt = &at
+	}
+}
+
+func withType(t Type, at time.Time) func(*Remark) {
+	return func(r *Remark) {
+		r.Type = t
+		r.UpdatedAt = &at
+	}
+}
+
+func resolved(at time.Time) func(*Remark) {
+	return func(r *Remark) {
+		r.State = StateResolved
+		r.UpdatedAt = &at
+	}
+}
+
+// on builds notes with remarks attached to one commit
+func on(commit string, remarks ...Remark) Notes {
+	return Notes{commit: &Remarks{Remarks: remarks}}
+}
+
+// plus combines notes on different commits
+func plus(notes ...Notes) Notes {
+	result := make(Notes)
+	for _, n := range notes {
+		for commit, remarks := range n {
+			result[commit] = remarks
+		}
+	}
+	return result
+}
+
+func TestMergeNotes(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      Notes
+		ours      Notes
+		theirs    Notes
+		want      Notes
+		conflicts []string // "<id> <field>"
+	}{
+		{
+			name:   "added on our side",
+			base:   on("c1", baseRemark("a")),
+			ours:   on("c1", baseRemark("a"), baseRemark("b")),
+			theirs: on("c1", baseRemark("a")),
+			want:   on("c1", baseRemark("a"), baseRemark("b")),
+		},
+		{
+			name:   "added on their side",
+			base:   on("c1", baseRemark("a")),
+			ours:   on("c1", baseRemark("a")),
+			theirs: plus(on("c1", baseRemark("a")), on("c2", baseRemark("b"))),
+			want:   plus(on("c1", baseRemark("a")), on("c2", baseRemark("b"))),
+		},
+		{
+			name:   "deleted by us, unchanged by them",
+			base:   on("c1", baseRemark("a"), baseRemark("b")),
+			ours:   on("c1", baseRemark("a")),
+			theirs: on("c1", baseRemark("a"), baseRemark("b")),
+			want:   on("c1", baseRemark("a")),
+		},
+		{
+			name:   "deleted by them, unchanged by us",
+			base:   on("c1", baseRemark("a")),
+			ours:   on("c1", baseRemark("a")),
+			theirs: Notes{},
+			want:   Notes{},
+		},
+		{
+			name:   "deleted by us, modified by them",
+			base:   on("c1", baseRemark("a")),
+			ours:   Notes{},
+			theirs: on("c1", baseRemark("a", withBody("Edited", later))),
+			want:   on("c1", baseRemark("a", withBody("Edited", later))),
+		},
+		{
+			name:   "deleted by them, modified by us",
+			base:   on("c1", baseRemark("a")),
+			ours:   on("c1", baseRemark("a", withType(TypeTodo, later))),
+			theirs: Notes{},
+			want:   on("c1", baseRemark("a", withType(TypeTodo, later))),
+		},
+		{
+			name:   "field changed on our side",
+			base:   on("c1", baseRemark("a")),
+			ours:   on("c1", baseRemark("a", withType(TypeTodo, earlier))),
+			theirs: on("c1", baseRemark("a")),
+			want:   on("c1", baseRemark("a", withType(TypeTodo, earlier))),
+		},
+		{
+			name:   "different fields changed on each side",
+			base:   on("c1", baseRemark("a")),
+			ours:   on("c1", baseRemark("a", withType(TypeTodo, earlier))),
+			theirs: on("c2", baseRemark("a", withBody("Edited", later))),
+			want:   on("c2", baseRemark("a", withType(TypeTodo, earlier), withBody("Edited", later))),
+		},
+		{
+			name:      "field changed on both sides, theirs last",
+			base:      on("c1", baseRemark("a")),
+			ours:      on("c1", baseRemark("a", withBody("Ours", earlier))),
+			theirs:    on("c1", baseRemark("a", withBody("Theirs", later))),
+			want:      on("c1", baseRemark("a", withBody("Theirs", later))),
+			conflicts: []string{"a body"},
+		},
+		{
+			name:      "field changed on both sides, ours last",
+			base:      on("c1", baseRemark("a")),
+			ours:      on("c1", baseRemark("a", withBody("Ours", later))),
+			theirs:    on("c1", baseRemark("a", withBody("Theirs", earlier))),
+			want:      on("c1", baseRemark("a", withBody("Ours", later))),
+			conflicts: []string{"a body"},
+		},
+		{
+			name:   "resolved stays resolved when the other side edits later",
+			base:   on("c1", baseRemark("a")),
+			ours:   on("c1", baseRemark("a", resolved(earlier))),
+			theirs: on("c1", baseRemark("a", withBody("Edited", later))),
+			want:   on("c1", baseRemark("a", withBody("Edited", later), resolved(later))),
+		},
+		{
+			name:   "resolved on their side only",
+			base:   on("c1", baseRemark("a")),
+			ours:   on("c1", baseRemark("a")),
+			theirs: on("c1", baseRemark("a", resolved(earlier))),
+			want:   on("c1", baseRemark("a", resolved(earlier))),
+		},
+		{
+			name:      "no base keeps the union",
+			base:      nil,
+			ours:      on("c1", baseRemark("a"), baseRemark("b", withBody("Ours", earlier))),
+			theirs:    plus(on("c1", baseRemark("b", withBody("Theirs", later))), on("c2", baseRemark("c"))),
+			want:      plus(on("c1", baseRemark("a"), baseRemark("b", withBody("Theirs", later))), on("c2", baseRemark("c"))),
+			conflicts: []string{"b body"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := MergeNotes(tt.base, tt.ours, tt.theirs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeNotes() =\n%s\nwant\n%s", dumpNotes(got), dumpNotes(tt.want))
+			}
+
+			var gotConflicts []string
+			for _, c := range conflicts {
+				gotConflicts = append(gotConflicts, c.ID+" "+c.Field)
+			}
+			if !reflect.DeepEqual(gotConflicts, tt.conflicts) {
+				t.Errorf("conflicts = %v, want %v", gotConflicts, tt.conflicts)
+			}
+		})
+	}
+}
+
+func dumpNotes(notes Notes) string {
+	var out string
+	for commit, remarks := range notes {
+		data, _ := remarks.Marshal()
+		out += commit + ":\n" + string(data)
+	}
+	return out
+}
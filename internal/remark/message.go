@@ -0,0 +1,73 @@
+package remark
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// messageRemarkPattern matches a remark written in a commit message as
+// "Remark(todo): body", or "Remark: body" for a thought
+var messageRemarkPattern = regexp.MustCompile(`^Remark(?:\(([A-Za-z]+)\))?:\s*(.*)$`)
+
+// MessageRemark is a remark written in a commit message
+type MessageRemark struct {
+	Type Type   `yaml:"type"`
+	Body string `yaml:"body"`
+}
+
+// String formats the remark as it is written in a commit message
+func (r MessageRemark) String() string {
+	return fmt.Sprintf("Remark(%s): %s", r.Type, r.Body)
+}
+
+// ExtractFromMessage removes the remark lines from a commit message and
+// returns the rest of the message together with the remarks found
+func ExtractFromMessage(message string) (string, []MessageRemark, error) {
+	var kept []string
+	var remarks []MessageRemark
+
+	for _, line := range strings.Split(message, "\n") {
+		m := messageRemarkPattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			kept = append(kept, line)
+			continue
+		}
+
+		remarkType := TypeThought
+		if m[1] != "" {
+			if !ValidateType(strings.ToLower(m[1])) {
+				return "", nil, fmt.Errorf("invalid remark type in commit message: %s (must be thought, doubt, todo, or decision)", m[1])
+			}
+			remarkType = Type(strings.ToLower(m[1]))
+		}
+
+		body := strings.TrimSpace(m[2])
+		if body == "" {
+			return "", nil, fmt.Errorf("empty remark in commit message: %s", line)
+		}
+		remarks = append(remarks, MessageRemark{Type: remarkType, Body: body})
+	}
+
+	if len(remarks) == 0 {
+		return message, nil, nil
+	}
+	return collapseBlankLines(strings.Join(kept, "\n")), remarks, nil
+}
+
+// collapseBlankLines removes the runs of blank lines and the trailing
+// blank lines left behind by removed remark lines
+func collapseBlankLines(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		blank := strings.TrimSpace(line) == ""
+		if blank && (len(lines) == 0 || strings.TrimSpace(lines[len(lines)-1]) == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
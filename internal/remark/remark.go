@@ -1,6 +1,10 @@
 package remark
 
 import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +21,9 @@ const (
 	TypeDecision Type = "decision"
 )
 
+// Types lists all remark types in display order
+var Types = []Type{TypeThought, TypeDoubt, TypeTodo, TypeDecision}
+
 // State represents the state of a remark
 type State string
 
@@ -25,14 +32,105 @@ const (
 	StateResolved State = "resolved"
 )
 
+// Visibility controls whether a remark is shared with others
+type Visibility string
+
+const (
+	VisibilityPrivate Visibility = "private"
+	VisibilityShared  Visibility = "shared"
+)
+
 // Remark represents a single note attached to a commit
 type Remark struct {
-	ID        string    `yaml:"id"`
-	Type      Type      `yaml:"type"`
-	Branch    string    `yaml:"branch"`
-	State     State     `yaml:"state"`
-	CreatedAt time.Time `yaml:"created_at"`
-	Body      string    `yaml:"body"`
+	ID         string     `yaml:"id" json:"id"`
+	Type       Type       `yaml:"type" json:"type"`
+	Branch     string     `yaml:"branch" json:"branch"`
+	State      State      `yaml:"state" json:"state"`
+	CreatedAt  time.Time  `yaml:"created_at" json:"created_at"`
+	Body       string     `yaml:"body" json:"body"`
+	Author     string     `yaml:"author,omitempty" json:"author,omitempty"`
+	Anchor     *Anchor    `yaml:"anchor,omitempty" json:"anchor,omitempty"`
+	CopiedFrom string     `yaml:"copied_from,omitempty" json:"copied_from,omitempty"`
+	UpdatedAt  *time.Time `yaml:"updated_at,omitempty" json:"updated_at,omitempty"`
+	Visibility Visibility `yaml:"visibility,omitempty" json:"visibility,omitempty"`
+}
+
+// IsShared returns true if the remark is shared; remarks are private
+// unless marked otherwise
+func (r *Remark) IsShared() bool {
+	return r.Visibility == VisibilityShared
+}
+
+// Touch records that the remark was modified now
+func (r *Remark) Touch() {
+	now := time.Now().UTC()
+	r.UpdatedAt = &now
+}
+
+// LastModified returns when the remark was last modified, falling back
+// to its creation time
+func (r *Remark) LastModified() time.Time {
+	if r.UpdatedAt != nil {
+		return *r.UpdatedAt
+	}
+	return r.CreatedAt
+}
+
+// Anchor ties a remark to a file, and optionally a line range, as of
+// the commit the remark is attached to
+type Anchor struct {
+	Path      string `yaml:"path" json:"path"`
+	StartLine int    `yaml:"start_line,omitempty" json:"start_line,omitempty"`
+	EndLine   int    `yaml:"end_line,omitempty" json:"end_line,omitempty"`
+}
+
+// HasLines returns true if the anchor covers a line range
+func (a *Anchor) HasLines() bool {
+	return a.StartLine > 0 && a.EndLine >= a.StartLine
+}
+
+// MatchesPath returns true if the anchor is on the given path or
+// inside the given directory
+func (a *Anchor) MatchesPath(p string) bool {
+	if p == "." || p == "" {
+		return true
+	}
+	p = strings.TrimSuffix(p, "/")
+	return a.Path == p || strings.HasPrefix(a.Path, p+"/")
+}
+
+// String formats the anchor as path:start-end
+func (a *Anchor) String() string {
+	switch {
+	case !a.HasLines():
+		return a.Path
+	case a.StartLine == a.EndLine:
+		return fmt.Sprintf("%s:%d", a.Path, a.StartLine)
+	default:
+		return fmt.Sprintf("%s:%d-%d", a.Path, a.StartLine, a.EndLine)
+	}
+}
+
+// ParseLineRange parses a line range such as "40-62" or "40"
+func ParseLineRange(s string) (start, end int, err error) {
+	startStr, endStr, found := strings.Cut(s, "-")
+	if !found {
+		endStr = startStr
+	}
+
+	start, err = strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid line range: %s", s)
+	}
+	end, err = strconv.Atoi(strings.TrimSpace(endStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid line range: %s", s)
+	}
+
+	if start < 1 || end < start {
+		return 0, 0, fmt.Errorf("invalid line range: %s", s)
+	}
+	return start, end, nil
 }
 
 // Remarks is a container for multiple remarks on a single commit
@@ -52,6 +150,11 @@ func NewRemark(remarkType Type, branch, body string) Remark {
 	}
 }
 
+// NewID returns a new remark ID
+func NewID() string {
+	return generateShortUUID()
+}
+
 // generateShortUUID generates an 8-character UUID
 func generateShortUUID() string {
 	id := uuid.New()
@@ -66,11 +169,54 @@ func ParseRemarks(data []byte) (*Remarks, error) {
 
 	var remarks Remarks
 	if err := yaml.Unmarshal(data, &remarks); err != nil {
+		// The union notes merge strategy concatenates both documents
+		if docs := splitDocuments(data); len(docs) > 1 {
+			return parseConcatenated(docs)
+		}
 		return nil, err
 	}
 	return &remarks, nil
 }
 
+// splitDocuments splits concatenated notes at each top-level
+// "remarks:" key
+func splitDocuments(data []byte) [][]byte {
+	var docs [][]byte
+	start := 0
+	for i := 0; i < len(data); {
+		end := bytes.IndexByte(data[i:], '\n')
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += i + 1
+		}
+		if i > start && bytes.HasPrefix(data[i:], []byte("remarks:")) {
+			docs = append(docs, data[start:i])
+			start = i
+		}
+		i = end
+	}
+	return append(docs, data[start:])
+}
+
+// parseConcatenated parses concatenated documents and merges them by
+// remark ID, so remarks present in several documents appear once
+func parseConcatenated(docs [][]byte) (*Remarks, error) {
+	result := &Remarks{}
+	for _, doc := range docs {
+		var remarks Remarks
+		if err := yaml.Unmarshal(doc, &remarks); err != nil {
+			return nil, err
+		}
+
+		merged, _ := MergeNotes(nil, Notes{"": result}, Notes{"": &remarks})
+		if merged[""] != nil {
+			result = merged[""]
+		}
+	}
+	return result, nil
+}
+
 // Marshal converts Remarks to YAML
 func (r *Remarks) Marshal() ([]byte, error) {
 	return yaml.Marshal(r)
@@ -110,6 +256,18 @@ func (r *Remarks) RemoveByID(id string) bool {
 	return false
 }
 
+// Resolve marks an active remark as resolved and returns true if it
+// was found and still active
+func (r *Remarks) Resolve(id string) bool {
+	found := r.FindByID(id)
+	if found == nil || found.State == StateResolved {
+		return false
+	}
+	found.State = StateResolved
+	found.Touch()
+	return true
+}
+
 // ActiveForBranch returns all active remarks for a given branch
 func (r *Remarks) ActiveForBranch(branch string) []Remark {
 	var result []Remark
@@ -126,6 +284,16 @@ func (r *Remarks) IsEmpty() bool {
 	return len(r.Remarks) == 0
 }
 
+// ValidateVisibility checks if a string is a valid visibility
+func ValidateVisibility(v string) bool {
+	switch Visibility(v) {
+	case VisibilityPrivate, VisibilityShared:
+		return true
+	default:
+		return false
+	}
+}
+
 // ValidateType checks if a string is a valid remark type
 func ValidateType(t string) bool {
 	switch Type(t) {
@@ -135,4 +303,3 @@ func ValidateType(t string) bool {
 		return false
 	}
 }
-
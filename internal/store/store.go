@@ -1,6 +1,8 @@
 package store
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/Enigama/git-remarks/internal/git"
@@ -10,27 +12,102 @@ import (
 // Store handles reading and writing remarks to git notes
 type Store struct {
 	notesRef string
+	// shared mirrors the shared remarks of every note to a ref that is
+	// safe to push; reads merge both views
+	shared *Store
 }
 
 // New creates a new Store
 func New() *Store {
 	return &Store{
 		notesRef: git.NotesRef,
+		shared:   NewWithRef(git.SharedNotesRef),
 	}
 }
 
+// Shared returns the store of the shared remarks ref
+func Shared() *Store {
+	return NewWithRef(git.SharedNotesRef)
+}
+
+// NewWithRef creates a Store for another notes ref, such as
+// "commits" or "refs/notes/review"
+func NewWithRef(ref string) *Store {
+	return &Store{
+		notesRef: ref,
+	}
+}
+
+// Ref returns the full notes ref of the store
+func (s *Store) Ref() string {
+	if strings.HasPrefix(s.notesRef, "refs/") {
+		return s.notesRef
+	}
+	return "refs/notes/" + s.notesRef
+}
+
 // Get retrieves remarks for a commit
 func (s *Store) Get(commit string) (*remark.Remarks, error) {
+	output, err := s.GetRaw(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	remarks, err := remark.ParseRemarks([]byte(output))
+	if err != nil || s.shared == nil {
+		return remarks, err
+	}
+
+	shared, err := s.shared.Get(commit)
+	if err != nil {
+		return nil, err
+	}
+	return mergeViews(remarks, shared), nil
+}
+
+// mergeViews combines the private and shared remarks on a commit,
+// keeping the most recently modified version of each remark
+func mergeViews(private, shared *remark.Remarks) *remark.Remarks {
+	result := &remark.Remarks{Remarks: append([]remark.Remark(nil), private.Remarks...)}
+	for _, r := range shared.Remarks {
+		existing := result.FindByID(r.ID)
+		if existing == nil {
+			result.Add(r)
+			continue
+		}
+		if r.LastModified().After(existing.LastModified()) {
+			*existing = r
+		}
+	}
+	return result
+}
+
+// sharedSubset returns the shared remarks in a note
+func sharedSubset(remarks *remark.Remarks) *remark.Remarks {
+	subset := &remark.Remarks{}
+	if remarks == nil {
+		return subset
+	}
+	for _, r := range remarks.Remarks {
+		if r.IsShared() {
+			subset.Add(r)
+		}
+	}
+	return subset
+}
+
+// GetRaw retrieves the unparsed note for a commit, or an empty string
+// if there is none
+func (s *Store) GetRaw(commit string) (string, error) {
 	output, err := git.Run("notes", "--ref="+s.notesRef, "show", commit)
 	if err != nil {
 		// No notes found for this commit
 		if strings.Contains(strings.ToLower(err.Error()), "no note found") {
-			return &remark.Remarks{}, nil
+			return "", nil
 		}
-		return nil, err
+		return "", err
 	}
-
-	return remark.ParseRemarks([]byte(output))
+	return output, nil
 }
 
 // Save writes remarks to a commit, overwriting any existing notes
@@ -45,21 +122,282 @@ func (s *Store) Save(commit string, remarks *remark.Remarks) error {
 		return err
 	}
 
-	_, err = git.RunWithStdin(string(data), "notes", "--ref="+s.notesRef, "add", "-f", "-F", "-", commit)
+	if _, err := git.RunWithStdin(string(data), "notes", "--ref="+s.notesRef, "add", "-f", "-F", "-", commit); err != nil {
+		return err
+	}
+
+	if s.shared == nil {
+		return nil
+	}
+	return s.shared.Save(commit, sharedSubset(remarks))
+}
+
+// SaveBatch writes remarks for many commits in a single notes commit,
+// overwriting any existing notes on those commits. Empty remarks remove
+// the notes from their commit.
+func (s *Store) SaveBatch(batch map[string]*remark.Remarks, message string) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := s.writeNotesCommit(batch, message, ""); err != nil {
+		return err
+	}
+
+	return s.saveSharedSubsets(batch, message)
+}
+
+// SyncShared rewrites the shared mirror of commits from the store's own
+// notes, after those were changed outside the store, e.g. by 'git notes
+// merge'. Shared remarks modified more recently in the mirror are kept.
+func (s *Store) SyncShared(commits []string, message string) error {
+	if s.shared == nil || len(commits) == 0 {
+		return nil
+	}
+
+	batch := make(map[string]*remark.Remarks)
+	for _, commit := range commits {
+		output, err := s.GetRaw(commit)
+		if err != nil {
+			return err
+		}
+		remarks, err := remark.ParseRemarks([]byte(output))
+		if err != nil {
+			return err
+		}
+		shared, err := s.shared.Get(commit)
+		if err != nil {
+			return err
+		}
+		for i, r := range remarks.Remarks {
+			if newer := shared.FindByID(r.ID); newer != nil && newer.LastModified().After(r.LastModified()) {
+				remarks.Remarks[i] = *newer
+			}
+		}
+		batch[commit] = remarks
+	}
+	return s.saveSharedSubsets(batch, message)
+}
+
+// DropSharedDeletions removes from the store's own notes the shared
+// remarks that were in the shared ref at before but are gone from it
+// now, e.g. deleted on another clone and merged in by sync. Otherwise
+// their private copies would be mirrored back on the next save. Copies
+// modified since before are kept. It returns how many were removed.
+func (s *Store) DropSharedDeletions(before remark.Notes, message string) (int, error) {
+	if s.shared == nil || len(before) == 0 {
+		return 0, nil
+	}
+
+	after := remark.Notes{}
+	if tip := s.shared.Tip(); tip != "" {
+		var err error
+		if after, err = ListAt(tip); err != nil {
+			return 0, err
+		}
+	}
+
+	batch := make(map[string]*remark.Remarks)
+	dropped := 0
+	for commit, old := range before {
+		output, err := s.GetRaw(commit)
+		if err != nil {
+			return 0, err
+		}
+		own, err := remark.ParseRemarks([]byte(output))
+		if err != nil {
+			return 0, err
+		}
+
+		for _, r := range old.Remarks {
+			if current := after[commit]; current != nil && current.FindByID(r.ID) != nil {
+				continue
+			}
+			if mine := own.FindByID(r.ID); mine != nil && !mine.LastModified().After(r.LastModified()) {
+				own.RemoveByID(r.ID)
+				batch[commit] = own
+				dropped++
+			}
+		}
+	}
+
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	return dropped, s.writeNotesCommit(batch, message, "")
+}
+
+// saveSharedSubsets mirrors the shared remarks of batch to the shared
+// ref, leaving commits without shared remarks on either side alone
+func (s *Store) saveSharedSubsets(batch map[string]*remark.Remarks, message string) error {
+	if s.shared == nil {
+		return nil
+	}
+
+	annotated, err := s.shared.ListAnnotated()
+	if err != nil {
+		return err
+	}
+	hasShared := make(map[string]bool)
+	for _, object := range annotated {
+		hasShared[object] = true
+	}
+
+	sharedBatch := make(map[string]*remark.Remarks)
+	for commit, remarks := range batch {
+		subset := sharedSubset(remarks)
+		if !subset.IsEmpty() || hasShared[commit] {
+			sharedBatch[commit] = subset
+		}
+	}
+	return s.shared.SaveBatch(sharedBatch, message)
+}
+
+// SaveMerge writes a notes merge commit with the current tip and other
+// as parents, applying batch on top of the current tip
+func (s *Store) SaveMerge(batch map[string]*remark.Remarks, message, other string) error {
+	return s.writeNotesCommit(batch, message, other)
+}
+
+// writeNotesCommit writes batch as one notes commit with fast-import
+func (s *Store) writeNotesCommit(batch map[string]*remark.Remarks, message, mergeParent string) error {
+	ref := s.Ref()
+	ident, err := git.Run("var", "GIT_COMMITTER_IDENT")
+	if err != nil {
+		return err
+	}
+
+	var script strings.Builder
+	fmt.Fprintf(&script, "commit %s\ncommitter %s\ndata %d\n%s\n", ref, ident, len(message), message)
+
+	if tip := s.Tip(); tip != "" {
+		fmt.Fprintf(&script, "from %s\n", tip)
+	}
+	if mergeParent != "" {
+		fmt.Fprintf(&script, "merge %s\n", mergeParent)
+	}
+
+	// Sort for a deterministic notes commit
+	commits := make([]string, 0, len(batch))
+	for commit := range batch {
+		commits = append(commits, commit)
+	}
+	sort.Strings(commits)
+
+	for _, commit := range commits {
+		remarks := batch[commit]
+		if remarks == nil || remarks.IsEmpty() {
+			fmt.Fprintf(&script, "N %s %s\n", strings.Repeat("0", len(commit)), commit)
+			continue
+		}
+
+		data, err := remarks.Marshal()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&script, "N inline %s\ndata %d\n%s\n", commit, len(data), data)
+	}
+
+	_, err = git.RunWithStdin(script.String(), "fast-import", "--quiet")
 	return err
 }
 
+// Tip returns the notes commit the store's ref points to, or an empty
+// string if the ref does not exist yet
+func (s *Store) Tip() string {
+	tip, err := git.Run("rev-parse", "--verify", "--quiet", s.Ref())
+	if err != nil {
+		return ""
+	}
+	return tip
+}
+
+// SetTip points the store's ref at a notes commit
+func (s *Store) SetTip(commit string) error {
+	_, err := git.Run("update-ref", s.Ref(), commit)
+	return err
+}
+
+// ListAt returns the remarks in the notes tree of a notes commit, such
+// as a fetched or merge-base version of a notes ref
+func ListAt(rev string) (remark.Notes, error) {
+	output, err := git.Run("ls-tree", "-r", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make(remark.Notes)
+	if output == "" {
+		return notes, nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		// Format: <mode> blob <object>\t<path>, with fanout directories
+		meta, path, found := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+
+		content, err := git.Run("cat-file", "blob", fields[2])
+		if err != nil {
+			return nil, err
+		}
+		remarks, err := remark.ParseRemarks([]byte(content))
+		if err != nil || remarks.IsEmpty() {
+			continue
+		}
+		notes[strings.ReplaceAll(path, "/", "")] = remarks
+	}
+
+	return notes, nil
+}
+
+// ListAllAt returns all remarks as they were at a point in time, read
+// from the reflogs of the store's ref and its shared ref. when is any
+// date git accepts in ref@{<date>}.
+func (s *Store) ListAllAt(when string) (map[string]*remark.Remarks, error) {
+	result := make(map[string]*remark.Remarks)
+	if rev, err := git.Run("rev-parse", "--verify", "--quiet", s.Ref()+"@{"+when+"}"); err == nil && rev != "" {
+		notes, err := ListAt(rev)
+		if err != nil {
+			return nil, err
+		}
+		for commit, remarks := range notes {
+			result[commit] = remarks
+		}
+	}
+	if s.shared == nil {
+		return result, nil
+	}
+
+	shared, err := s.shared.ListAllAt(when)
+	if err != nil {
+		return nil, err
+	}
+	for commit, remarks := range shared {
+		if existing, ok := result[commit]; ok {
+			result[commit] = mergeViews(existing, remarks)
+		} else {
+			result[commit] = remarks
+		}
+	}
+	return result, nil
+}
+
 // Remove removes notes from a commit
 func (s *Store) Remove(commit string) error {
 	_, err := git.Run("notes", "--ref="+s.notesRef, "remove", commit)
 	if err != nil {
 		// Ignore error if no notes exist
-		if strings.Contains(strings.ToLower(err.Error()), "no note found") {
-			return nil
+		if !strings.Contains(strings.ToLower(err.Error()), "no note") {
+			return err
 		}
-		return err
 	}
-	return nil
+
+	if s.shared == nil {
+		return nil
+	}
+	return s.shared.Remove(commit)
 }
 
 // Add adds a new remark to a commit
@@ -73,20 +411,42 @@ func (s *Store) Add(commit string, r remark.Remark) error {
 	return s.Save(commit, remarks)
 }
 
-// Resolve marks a remark as resolved by removing it
+// Resolve marks a remark as resolved. Resolved remarks are kept so
+// decisions and history remain available to changelog and export
 func (s *Store) Resolve(commit, remarkID string) (bool, error) {
 	remarks, err := s.Get(commit)
 	if err != nil {
 		return false, err
 	}
 
-	if !remarks.RemoveByID(remarkID) {
+	if !remarks.Resolve(remarkID) {
 		return false, nil
 	}
 
 	return true, s.Save(commit, remarks)
 }
 
+// ResolveIDs resolves several remarks on one commit with a single write
+// and returns how many were found
+func (s *Store) ResolveIDs(commit string, ids []string) (int, error) {
+	remarks, err := s.Get(commit)
+	if err != nil {
+		return 0, err
+	}
+
+	resolved := 0
+	for _, id := range ids {
+		if remarks.Resolve(id) {
+			resolved++
+		}
+	}
+
+	if resolved == 0 {
+		return 0, nil
+	}
+	return resolved, s.Save(commit, remarks)
+}
+
 // UpdateRemark updates an existing remark
 func (s *Store) UpdateRemark(commit string, r remark.Remark) error {
 	remarks, err := s.Get(commit)
@@ -100,47 +460,87 @@ func (s *Store) UpdateRemark(commit string, r remark.Remark) error {
 	}
 
 	*found = r
+	found.Touch()
 	return s.Save(commit, remarks)
 }
 
 // ListAllWithRemarks returns all commits that have remarks
 func (s *Store) ListAllWithRemarks() (map[string]*remark.Remarks, error) {
-	// List all notes in the remarks ref
+	commits, err := s.ListAnnotated()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*remark.Remarks)
+	for _, commit := range commits {
+		remarks, err := s.Get(commit)
+		if err != nil {
+			continue
+		}
+		if !remarks.IsEmpty() {
+			result[commit] = remarks
+		}
+	}
+
+	return result, nil
+}
+
+// ListAnnotated returns all objects that have a note in the store's ref,
+// or in its shared ref
+func (s *Store) ListAnnotated() ([]string, error) {
+	objects, err := s.listNotes()
+	if err != nil || s.shared == nil {
+		return objects, err
+	}
+
+	shared, err := s.shared.ListAnnotated()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, object := range objects {
+		seen[object] = true
+	}
+	for _, object := range shared {
+		if !seen[object] {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// listNotes returns all objects that have a note in the store's own ref
+func (s *Store) listNotes() ([]string, error) {
+	// List all notes in the ref
 	output, err := git.Run("notes", "--ref="+s.notesRef, "list")
 	if err != nil {
 		// No notes exist
 		if strings.Contains(err.Error(), "No notes") {
-			return make(map[string]*remark.Remarks), nil
+			return nil, nil
 		}
 		// Check for unborn branch or no notes
 		if strings.Contains(err.Error(), "does not have any notes") {
-			return make(map[string]*remark.Remarks), nil
+			return nil, nil
 		}
 		return nil, err
 	}
 
 	if output == "" {
-		return make(map[string]*remark.Remarks), nil
+		return nil, nil
 	}
 
-	result := make(map[string]*remark.Remarks)
+	var objects []string
 	lines := strings.Split(output, "\n")
 	for _, line := range lines {
 		// Format: <note-object> <annotated-object>
 		parts := strings.Fields(line)
 		if len(parts) >= 2 {
-			commit := parts[1]
-			remarks, err := s.Get(commit)
-			if err != nil {
-				continue
-			}
-			if !remarks.IsEmpty() {
-				result[commit] = remarks
-			}
+			objects = append(objects, parts[1])
 		}
 	}
 
-	return result, nil
+	return objects, nil
 }
 
 // Migrate moves remarks from one commit to another
@@ -168,6 +568,32 @@ func (s *Store) Migrate(oldCommit, newCommit string) error {
 	return s.Remove(oldCommit)
 }
 
+// MoveRemark moves a single remark from one commit to another,
+// keeping its ID and metadata
+func (s *Store) MoveRemark(fromCommit, toCommit string, r remark.Remark) (bool, error) {
+	fromRemarks, err := s.Get(fromCommit)
+	if err != nil {
+		return false, err
+	}
+
+	if !fromRemarks.RemoveByID(r.ID) {
+		return false, nil
+	}
+
+	toRemarks, err := s.Get(toCommit)
+	if err != nil {
+		return false, err
+	}
+
+	r.Touch()
+	toRemarks.Add(r)
+	if err := s.Save(toCommit, toRemarks); err != nil {
+		return false, err
+	}
+
+	return true, s.Save(fromCommit, fromRemarks)
+}
+
 // FindRemarkByID searches all remarks to find one by ID
 // Returns the commit SHA and the remark if found
 func (s *Store) FindRemarkByID(id string) (string, *remark.Remark, error) {
@@ -0,0 +1,624 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Enigama/git-remarks/internal/anchor"
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+)
+
+// maxCommits limits how much history is shown in the commit list
+const maxCommits = 500
+
+// EditFunc opens a remark in an editor and returns the new body and type
+type EditFunc func(shortSHA string, r *remark.Remark) (body string, newType string, err error)
+
+// RemarkStore is where the App reads and writes remarks.
+// *store.Store implements it.
+type RemarkStore interface {
+	ListAllWithRemarks() (map[string]*remark.Remarks, error)
+	Add(commit string, r remark.Remark) error
+	Resolve(commit, remarkID string) (bool, error)
+	UpdateRemark(commit string, r remark.Remark) error
+	MoveRemark(fromCommit, toCommit string, r remark.Remark) (bool, error)
+}
+
+// History lists the commits the App shows, newest first, and renders
+// the diff of one of them
+type History interface {
+	Commits(limit int) ([]git.CommitInfo, error)
+	Show(commit string) (string, error)
+}
+
+// GitHistory is the History of the current repository's HEAD
+type GitHistory struct{}
+
+// Commits returns up to limit commits reachable from HEAD
+func (GitHistory) Commits(limit int) ([]git.CommitInfo, error) {
+	head, err := git.GetHEAD()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get HEAD: %w", err)
+	}
+
+	return git.GetHistory(head, limit)
+}
+
+// Show returns the stat and patch of a commit
+func (GitHistory) Show(commit string) (string, error) {
+	return git.RunRaw("show", "--stat", "--patch", "--no-color", commit)
+}
+
+// Options configures an App
+type Options struct {
+	// Branch is the current branch, used to scope remarks and for new
+	// remarks. Empty means detached HEAD.
+	Branch string
+	// Author is recorded on new remarks
+	Author string
+	// Edit is called to edit a remark body. Editing is disabled if nil.
+	Edit EditFunc
+}
+
+type pane int
+
+const (
+	paneCommits pane = iota
+	paneRemarks
+)
+
+type mode int
+
+const (
+	modeNormal mode = iota
+	modePrompt
+	modeConfirm
+	modeMove
+	modeDiff
+	modeHelp
+)
+
+// commitItem is a row in the commit list
+type commitItem struct {
+	Info    git.CommitInfo
+	Remarks []remark.Remark // remarks visible with the current scope and filter
+}
+
+// App is the interactive remarks browser
+type App struct {
+	term    Terminal
+	history History
+	store   RemarkStore
+	opts    Options
+
+	allBranches bool
+	typeFilter  remark.Type // empty = all types
+
+	commits      []commitItem
+	commitCursor int
+	remarkCursor int
+	focus        pane
+	mode         mode
+	status       string
+
+	promptLabel  string
+	promptInput  []rune
+	promptSubmit func(string)
+	confirmYes   func()
+
+	moveFrom   string
+	moveRemark remark.Remark
+
+	diffLines  []string
+	diffOffset int
+
+	quit bool
+}
+
+// New creates an App on the given terminal
+func New(term Terminal, history History, s RemarkStore, opts Options) *App {
+	return &App{
+		term:        term,
+		history:     history,
+		store:       s,
+		opts:        opts,
+		allBranches: opts.Branch == "",
+	}
+}
+
+// Run draws the UI and handles keys until the user quits or the
+// terminal runs out of input
+func (a *App) Run() error {
+	if err := a.reload(); err != nil {
+		return err
+	}
+
+	for !a.quit {
+		a.draw()
+
+		key, err := a.term.ReadKey()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		a.handleKey(key)
+	}
+	return nil
+}
+
+// reload reads history and remarks and rebuilds the commit list,
+// keeping the selection where possible
+func (a *App) reload() error {
+	selected := ""
+	if c := a.selectedCommit(); c != nil {
+		selected = c.Info.SHA
+	}
+
+	history, err := a.history.Commits(maxCommits)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	allRemarks, err := a.store.ListAllWithRemarks()
+	if err != nil {
+		return fmt.Errorf("failed to list remarks: %w", err)
+	}
+
+	a.commits = a.commits[:0]
+	for _, c := range history {
+		item := commitItem{Info: c}
+		if remarks, ok := allRemarks[c.SHA]; ok {
+			for _, r := range remarks.Remarks {
+				if a.visible(r) {
+					item.Remarks = append(item.Remarks, r)
+				}
+			}
+		}
+		a.commits = append(a.commits, item)
+	}
+
+	for i, c := range a.commits {
+		if c.Info.SHA == selected {
+			a.commitCursor = i
+		}
+	}
+	a.clampCursors()
+	return nil
+}
+
+// visible returns true if a remark passes the branch scope and type filter
+func (a *App) visible(r remark.Remark) bool {
+	if r.State != remark.StateActive {
+		return false
+	}
+	if !a.allBranches && r.Branch != a.opts.Branch {
+		return false
+	}
+	return a.typeFilter == "" || r.Type == a.typeFilter
+}
+
+func (a *App) selectedCommit() *commitItem {
+	if a.commitCursor < 0 || a.commitCursor >= len(a.commits) {
+		return nil
+	}
+	return &a.commits[a.commitCursor]
+}
+
+func (a *App) selectedRemark() *remark.Remark {
+	c := a.selectedCommit()
+	if c == nil || a.remarkCursor < 0 || a.remarkCursor >= len(c.Remarks) {
+		return nil
+	}
+	return &c.Remarks[a.remarkCursor]
+}
+
+func (a *App) clampCursors() {
+	a.commitCursor = clamp(a.commitCursor, 0, len(a.commits)-1)
+	if c := a.selectedCommit(); c != nil {
+		a.remarkCursor = clamp(a.remarkCursor, 0, len(c.Remarks)-1)
+	} else {
+		a.remarkCursor = 0
+	}
+}
+
+// handleKey dispatches a key press according to the current mode
+func (a *App) handleKey(key Key) {
+	if key.Code == KeyCtrlC {
+		a.quit = true
+		return
+	}
+
+	switch a.mode {
+	case modePrompt:
+		a.handlePromptKey(key)
+	case modeConfirm:
+		a.mode = modeNormal
+		if key.Code == KeyRune && (key.Rune == 'y' || key.Rune == 'Y') {
+			a.confirmYes()
+		} else {
+			a.status = "Cancelled"
+		}
+	case modeMove:
+		a.handleMoveKey(key)
+	case modeDiff:
+		a.handleDiffKey(key)
+	case modeHelp:
+		a.mode = modeNormal
+	default:
+		a.handleNormalKey(key)
+	}
+}
+
+func (a *App) handleNormalKey(key Key) {
+	a.status = ""
+
+	switch key.Code {
+	case KeyUp:
+		a.moveCursor(-1)
+		return
+	case KeyDown:
+		a.moveCursor(1)
+		return
+	case KeyPageUp:
+		a.moveCursor(-10)
+		return
+	case KeyPageDown:
+		a.moveCursor(10)
+		return
+	case KeyTab, KeyLeft, KeyRight:
+		a.switchPane(key.Code)
+		return
+	case KeyEnter:
+		a.focus = paneRemarks
+		return
+	case KeyEscape:
+		a.focus = paneCommits
+		return
+	case KeyRune:
+	default:
+		return
+	}
+
+	switch key.Rune {
+	case 'q':
+		a.quit = true
+	case 'j':
+		a.moveCursor(1)
+	case 'k':
+		a.moveCursor(-1)
+	case 'h':
+		a.focus = paneCommits
+	case 'l':
+		a.focus = paneRemarks
+	case 'a':
+		a.startAdd()
+	case 'e':
+		a.editSelected()
+	case 'r':
+		a.resolveSelected()
+	case 't':
+		a.retypeSelected()
+	case 'm':
+		a.startMove()
+	case 'f':
+		a.cycleTypeFilter()
+	case 'b':
+		a.toggleBranchScope()
+	case 'd':
+		a.showDiff()
+	case 'g':
+		a.reloadWithStatus("Reloaded")
+	case '?':
+		a.mode = modeHelp
+	}
+}
+
+func (a *App) moveCursor(delta int) {
+	if a.focus == paneRemarks {
+		if c := a.selectedCommit(); c != nil {
+			a.remarkCursor = clamp(a.remarkCursor+delta, 0, len(c.Remarks)-1)
+		}
+		return
+	}
+	a.commitCursor = clamp(a.commitCursor+delta, 0, len(a.commits)-1)
+	a.remarkCursor = 0
+}
+
+func (a *App) switchPane(code KeyCode) {
+	switch {
+	case code == KeyLeft:
+		a.focus = paneCommits
+	case code == KeyRight:
+		a.focus = paneRemarks
+	case a.focus == paneCommits:
+		a.focus = paneRemarks
+	default:
+		a.focus = paneCommits
+	}
+}
+
+// requireRemark returns the selected remark or sets a status message
+func (a *App) requireRemark() *remark.Remark {
+	r := a.selectedRemark()
+	if r == nil {
+		a.status = "No remark selected"
+	}
+	return r
+}
+
+func (a *App) startAdd() {
+	c := a.selectedCommit()
+	if c == nil {
+		return
+	}
+	if a.opts.Branch == "" {
+		a.status = "Not on a branch, cannot add remarks"
+		return
+	}
+
+	remarkType := remark.TypeThought
+	if a.typeFilter != "" {
+		remarkType = a.typeFilter
+	}
+
+	commit := c.Info
+	a.prompt(fmt.Sprintf("New %s on %s: ", remarkType, commit.ShortSHA), func(body string) {
+		if strings.TrimSpace(body) == "" {
+			a.status = "Empty remark, nothing added"
+			return
+		}
+		r := remark.NewRemark(remarkType, a.opts.Branch, body)
+		r.Author = a.opts.Author
+		if err := a.store.Add(commit.SHA, r); err != nil {
+			a.status = "Failed to add remark: " + err.Error()
+			return
+		}
+		a.reloadWithStatus(fmt.Sprintf("Added [%s] to %s", r.ID, commit.ShortSHA))
+	})
+}
+
+func (a *App) editSelected() {
+	r := a.requireRemark()
+	if r == nil {
+		return
+	}
+	if a.opts.Edit == nil {
+		a.status = "Editing is not available"
+		return
+	}
+
+	c := a.selectedCommit()
+	edited := *r
+
+	if err := a.term.Suspend(); err != nil {
+		a.status = "Failed to suspend terminal: " + err.Error()
+		return
+	}
+	body, newType, err := a.opts.Edit(c.Info.ShortSHA, &edited)
+	if resumeErr := a.term.Resume(); resumeErr != nil && err == nil {
+		err = resumeErr
+	}
+	if err != nil {
+		a.status = "Edit failed: " + err.Error()
+		return
+	}
+
+	if strings.TrimSpace(body) == "" {
+		a.status = "Empty body, remark not changed"
+		return
+	}
+
+	edited.Body = body
+	if newType != "" && remark.ValidateType(newType) {
+		edited.Type = remark.Type(newType)
+	}
+	a.update(c.Info.SHA, edited, fmt.Sprintf("Updated [%s]", edited.ID))
+}
+
+func (a *App) resolveSelected() {
+	r := a.requireRemark()
+	if r == nil {
+		return
+	}
+
+	commit := a.selectedCommit().Info.SHA
+	id := r.ID
+	a.confirm(fmt.Sprintf("Resolve [%s]? (y/n)", id), func() {
+		if _, err := a.store.Resolve(commit, id); err != nil {
+			a.status = "Failed to resolve: " + err.Error()
+			return
+		}
+		a.reloadWithStatus(fmt.Sprintf("Resolved [%s]", id))
+	})
+}
+
+func (a *App) retypeSelected() {
+	r := a.requireRemark()
+	if r == nil {
+		return
+	}
+
+	retyped := *r
+	retyped.Type = nextType(r.Type)
+	a.update(a.selectedCommit().Info.SHA, retyped, fmt.Sprintf("[%s] is now a %s", r.ID, retyped.Type))
+}
+
+// update saves a changed remark and reloads
+func (a *App) update(commit string, r remark.Remark, status string) {
+	if err := a.store.UpdateRemark(commit, r); err != nil {
+		a.status = "Failed to update: " + err.Error()
+		return
+	}
+	a.reloadWithStatus(status)
+}
+
+func (a *App) startMove() {
+	r := a.requireRemark()
+	if r == nil {
+		return
+	}
+
+	a.moveFrom = a.selectedCommit().Info.SHA
+	a.moveRemark = *r
+	a.focus = paneCommits
+	a.mode = modeMove
+}
+
+func (a *App) handleMoveKey(key Key) {
+	switch {
+	case key.Code == KeyEscape || (key.Code == KeyRune && key.Rune == 'q'):
+		a.mode = modeNormal
+		a.status = "Move cancelled"
+	case key.Code == KeyUp || (key.Code == KeyRune && key.Rune == 'k'):
+		a.moveCursor(-1)
+	case key.Code == KeyDown || (key.Code == KeyRune && key.Rune == 'j'):
+		a.moveCursor(1)
+	case key.Code == KeyPageUp:
+		a.moveCursor(-10)
+	case key.Code == KeyPageDown:
+		a.moveCursor(10)
+	case key.Code == KeyEnter || (key.Code == KeyRune && key.Rune == 'm'):
+		a.mode = modeNormal
+		a.finishMove()
+	}
+}
+
+func (a *App) finishMove() {
+	target := a.selectedCommit()
+	if target == nil || target.Info.SHA == a.moveFrom {
+		a.status = "Move cancelled"
+		return
+	}
+
+	moved := a.moveRemark
+	moved.Anchor = anchor.Retarget(a.moveFrom, target.Info.SHA, moved.Anchor)
+
+	if _, err := a.store.MoveRemark(a.moveFrom, target.Info.SHA, moved); err != nil {
+		a.status = "Failed to move: " + err.Error()
+		return
+	}
+	a.reloadWithStatus(fmt.Sprintf("Moved [%s] to %s", moved.ID, target.Info.ShortSHA))
+}
+
+func (a *App) cycleTypeFilter() {
+	if a.typeFilter == "" {
+		a.typeFilter = remark.Types[0]
+	} else if next := nextType(a.typeFilter); next == remark.Types[0] {
+		a.typeFilter = ""
+	} else {
+		a.typeFilter = next
+	}
+	a.remarkCursor = 0
+	a.reloadWithStatus("")
+}
+
+func (a *App) toggleBranchScope() {
+	if a.opts.Branch == "" {
+		a.status = "Not on a branch, showing all branches"
+		return
+	}
+	a.allBranches = !a.allBranches
+	a.remarkCursor = 0
+	a.reloadWithStatus("")
+}
+
+func (a *App) showDiff() {
+	c := a.selectedCommit()
+	if c == nil {
+		return
+	}
+
+	output, err := a.history.Show(c.Info.SHA)
+	if err != nil {
+		a.status = "Failed to show diff: " + err.Error()
+		return
+	}
+
+	a.diffLines = strings.Split(strings.TrimRight(output, "\n"), "\n")
+	a.diffOffset = 0
+	a.mode = modeDiff
+}
+
+func (a *App) handleDiffKey(key Key) {
+	_, height := a.term.Size()
+	page := height - 2
+
+	switch {
+	case key.Code == KeyEscape || (key.Code == KeyRune && (key.Rune == 'q' || key.Rune == 'd')):
+		a.mode = modeNormal
+	case key.Code == KeyUp || (key.Code == KeyRune && key.Rune == 'k'):
+		a.diffOffset--
+	case key.Code == KeyDown || key.Code == KeyEnter || (key.Code == KeyRune && key.Rune == 'j'):
+		a.diffOffset++
+	case key.Code == KeyPageUp || (key.Code == KeyRune && key.Rune == 'b'):
+		a.diffOffset -= page
+	case key.Code == KeyPageDown || (key.Code == KeyRune && key.Rune == ' '):
+		a.diffOffset += page
+	}
+	a.diffOffset = clamp(a.diffOffset, 0, len(a.diffLines)-page)
+}
+
+// prompt asks for a line of text in the status bar
+func (a *App) prompt(label string, submit func(string)) {
+	a.mode = modePrompt
+	a.promptLabel = label
+	a.promptInput = nil
+	a.promptSubmit = submit
+}
+
+func (a *App) handlePromptKey(key Key) {
+	switch key.Code {
+	case KeyEscape:
+		a.mode = modeNormal
+		a.status = "Cancelled"
+	case KeyEnter:
+		a.mode = modeNormal
+		a.promptSubmit(string(a.promptInput))
+	case KeyBackspace:
+		if len(a.promptInput) > 0 {
+			a.promptInput = a.promptInput[:len(a.promptInput)-1]
+		}
+	case KeyRune:
+		a.promptInput = append(a.promptInput, key.Rune)
+	}
+}
+
+// confirm asks a yes/no question in the status bar
+func (a *App) confirm(label string, yes func()) {
+	a.mode = modeConfirm
+	a.status = label
+	a.confirmYes = yes
+}
+
+func (a *App) reloadWithStatus(status string) {
+	if err := a.reload(); err != nil {
+		a.status = err.Error()
+		return
+	}
+	a.status = status
+}
+
+// nextType returns the type after t in display order, wrapping around
+func nextType(t remark.Type) remark.Type {
+	for i, known := range remark.Types {
+		if known == t {
+			return remark.Types[(i+1)%len(remark.Types)]
+		}
+	}
+	return remark.Types[0]
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
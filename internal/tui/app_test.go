@@ -0,0 +1,268 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Enigama/git-remarks/internal/git"
+	"github.com/Enigama/git-remarks/internal/remark"
+)
+
+var (
+	headSHA   = strings.Repeat("a", 40)
+	parentSHA = strings.Repeat("b", 40)
+	rootSHA   = strings.Repeat("c", 40)
+)
+
+// fakeHistory is a fixed History of three commits
+type fakeHistory struct{}
+
+func (fakeHistory) Commits(limit int) ([]git.CommitInfo, error) {
+	return []git.CommitInfo{
+		{SHA: headSHA, ShortSHA: "aaaaaaa", Subject: "Add parser"},
+		{SHA: parentSHA, ShortSHA: "bbbbbbb", Subject: "Fix lexer"},
+		{SHA: rootSHA, ShortSHA: "ccccccc", Subject: "Initial commit"},
+	}, nil
+}
+
+func (fakeHistory) Show(commit string) (string, error) {
+	return "diff --git a/parser.go b/parser.go\n+func parse() {}\n", nil
+}
+
+// memStore is an in-memory RemarkStore
+type memStore map[string]*remark.Remarks
+
+func (m memStore) ListAllWithRemarks() (map[string]*remark.Remarks, error) {
+	return m, nil
+}
+
+func (m memStore) Add(commit string, r remark.Remark) error {
+	if m[commit] == nil {
+		m[commit] = &remark.Remarks{}
+	}
+	m[commit].Add(r)
+	return nil
+}
+
+func (m memStore) Resolve(commit, remarkID string) (bool, error) {
+	if m[commit] == nil {
+		return false, nil
+	}
+	return m[commit].Resolve(remarkID), nil
+}
+
+func (m memStore) UpdateRemark(commit string, r remark.Remark) error {
+	existing := m.find(commit, r.ID)
+	if existing == nil {
+		return fmt.Errorf("remark not found: %s", r.ID)
+	}
+	*existing = r
+	return nil
+}
+
+func (m memStore) MoveRemark(fromCommit, toCommit string, r remark.Remark) (bool, error) {
+	if m[fromCommit] == nil || !m[fromCommit].RemoveByID(r.ID) {
+		return false, nil
+	}
+	return true, m.Add(toCommit, r)
+}
+
+func (m memStore) find(commit, id string) *remark.Remark {
+	if m[commit] == nil {
+		return nil
+	}
+	return m[commit].FindByID(id)
+}
+
+func newRemark(id string, t remark.Type, branch, body string) remark.Remark {
+	r := remark.NewRemark(t, branch, body)
+	r.ID = id
+	return r
+}
+
+// testStore has a todo on HEAD, a thought on its parent and a decision
+// on the parent scoped to another branch
+func testStore() memStore {
+	return memStore{
+		headSHA: {Remarks: []remark.Remark{
+			newRemark("t0d0", remark.TypeTodo, "main", "Handle EOF"),
+		}},
+		parentSHA: {Remarks: []remark.Remark{
+			newRemark("th1n", remark.TypeThought, "main", "Lexer is slow"),
+			newRemark("dec1", remark.TypeDecision, "other", "Keep the regex"),
+		}},
+	}
+}
+
+// runApp runs an App on a scripted terminal until the keys run out and
+// returns the terminal
+func runApp(t *testing.T, s memStore, opts Options, keys string) *ScriptedTerminal {
+	t.Helper()
+	term := NewScriptedTerminal(100, 20, ScriptKeys(keys)...)
+	if err := New(term, fakeHistory{}, s, opts).Run(); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	return term
+}
+
+var mainOpts = Options{Branch: "main", Author: "Test <test@example.com>"}
+
+func TestAppFrames(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "initial frame lists history and HEAD's remarks",
+			keys:    "",
+			want:    []string{"git remarks · main · all types · 2 remarks", "aaaaaaa (1) Add parser", "bbbbbbb (1) Fix lexer", "ccccccc", "[t0d0] todo", "Handle EOF"},
+			notWant: []string{"Lexer is slow", "Keep the regex"},
+		},
+		{
+			name:    "moving down selects the parent",
+			keys:    "j",
+			want:    []string{"bbbbbbb Fix lexer", "[th1n] thought", "Lexer is slow"},
+			notWant: []string{"Handle EOF", "Keep the regex"},
+		},
+		{
+			name: "branch scope shows remarks of all branches",
+			keys: "jb",
+			want: []string{"all branches", "3 remarks", "Keep the regex", "· other"},
+		},
+		{
+			name:    "type filter hides other types",
+			keys:    "f",
+			want:    []string{"main · thought · 1 remark", "No remarks. Press a to add one."},
+			notWant: []string{"Handle EOF"},
+		},
+		{
+			name: "diff view shows the selected commit",
+			keys: "d",
+			want: []string{"diff --git a/parser.go b/parser.go", "+func parse() {}"},
+		},
+		{
+			name: "help lists the keys",
+			keys: "?",
+			want: []string{"resolve the selected remark", "Press any key to continue"},
+		},
+		{
+			name:    "declining the confirmation cancels resolve",
+			keys:    "\trn",
+			want:    []string{"Cancelled", "Handle EOF"},
+			notWant: []string{"Resolved"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := runApp(t, testStore(), mainOpts, tt.keys).LastFrame()
+			for _, want := range tt.want {
+				if !strings.Contains(frame, want) {
+					t.Errorf("frame does not contain %q:\n%s", want, frame)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(frame, notWant) {
+					t.Errorf("frame contains %q:\n%s", notWant, frame)
+				}
+			}
+		})
+	}
+}
+
+func TestAppAddsRemark(t *testing.T) {
+	s := testStore()
+	frame := runApp(t, s, mainOpts, "aCheck bounds\n").LastFrame()
+
+	remarks := s[headSHA].Remarks
+	if len(remarks) != 2 {
+		t.Fatalf("got %d remarks on HEAD, want 2", len(remarks))
+	}
+	added := remarks[1]
+	if added.Body != "Check bounds" || added.Type != remark.TypeThought || added.Branch != "main" || added.Author != mainOpts.Author {
+		t.Errorf("added remark = %+v", added)
+	}
+	if !strings.Contains(frame, fmt.Sprintf("Added [%s] to aaaaaaa", added.ID)) || !strings.Contains(frame, "Check bounds") {
+		t.Errorf("frame does not show the added remark:\n%s", frame)
+	}
+}
+
+func TestAppDoesNotAddWithoutBranch(t *testing.T) {
+	s := testStore()
+	frame := runApp(t, s, Options{}, "a").LastFrame()
+
+	if len(s[headSHA].Remarks) != 1 {
+		t.Errorf("remark added in detached HEAD")
+	}
+	if !strings.Contains(frame, "Not on a branch, cannot add remarks") {
+		t.Errorf("frame does not explain why:\n%s", frame)
+	}
+}
+
+func TestAppResolvesRemark(t *testing.T) {
+	s := testStore()
+	frame := runApp(t, s, mainOpts, "\try").LastFrame()
+
+	if got := s.find(headSHA, "t0d0").State; got != remark.StateResolved {
+		t.Errorf("state = %s, want resolved", got)
+	}
+	if !strings.Contains(frame, "Resolved [t0d0]") || strings.Contains(frame, "Handle EOF") {
+		t.Errorf("frame still shows the resolved remark:\n%s", frame)
+	}
+}
+
+func TestAppRetypesRemark(t *testing.T) {
+	s := testStore()
+	frame := runApp(t, s, mainOpts, "\tt").LastFrame()
+
+	if got := s.find(headSHA, "t0d0").Type; got != remark.TypeDecision {
+		t.Errorf("type = %s, want decision", got)
+	}
+	if !strings.Contains(frame, "[t0d0] is now a decision") {
+		t.Errorf("frame does not show the new type:\n%s", frame)
+	}
+}
+
+func TestAppEditsRemark(t *testing.T) {
+	s := testStore()
+	opts := mainOpts
+	opts.Edit = func(shortSHA string, r *remark.Remark) (string, string, error) {
+		return "Handle EOF and errors", "doubt", nil
+	}
+	frame := runApp(t, s, opts, "\te").LastFrame()
+
+	edited := s.find(headSHA, "t0d0")
+	if edited.Body != "Handle EOF and errors" || edited.Type != remark.TypeDoubt {
+		t.Errorf("edited remark = %+v", edited)
+	}
+	if !strings.Contains(frame, "Updated [t0d0]") || !strings.Contains(frame, "[t0d0] doubt") {
+		t.Errorf("frame does not show the edit:\n%s", frame)
+	}
+}
+
+func TestAppMovesRemark(t *testing.T) {
+	s := testStore()
+	frame := runApp(t, s, mainOpts, "\tmj\n").LastFrame()
+
+	if s.find(headSHA, "t0d0") != nil || s.find(parentSHA, "t0d0") == nil {
+		t.Errorf("remark not moved to the parent: %+v", s)
+	}
+	if !strings.Contains(frame, "Moved [t0d0] to bbbbbbb") || !strings.Contains(frame, "bbbbbbb (2) Fix lexer") {
+		t.Errorf("frame does not show the move:\n%s", frame)
+	}
+}
+
+func TestAppCancelsMove(t *testing.T) {
+	s := testStore()
+	frame := runApp(t, s, mainOpts, "\tmj\x1b").LastFrame()
+
+	if s.find(headSHA, "t0d0") == nil {
+		t.Errorf("remark moved although the move was cancelled")
+	}
+	if !strings.Contains(frame, "Move cancelled") {
+		t.Errorf("frame does not show the cancellation:\n%s", frame)
+	}
+}
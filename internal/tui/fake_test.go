@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ScriptedTerminal is a Terminal that replays a fixed sequence of keys
+// and records everything drawn, for driving the TUI without a real
+// terminal. ReadKey returns io.EOF once the script is exhausted.
+type ScriptedTerminal struct {
+	Keys   []Key
+	Width  int
+	Height int
+	Output bytes.Buffer
+}
+
+// NewScriptedTerminal creates a scripted terminal of the given size
+func NewScriptedTerminal(width, height int, keys ...Key) *ScriptedTerminal {
+	return &ScriptedTerminal{Keys: keys, Width: width, Height: height}
+}
+
+// ScriptKeys converts text into key presses. "\n" is Enter, "\t" is
+// Tab and "\x1b" is Escape; everything else is typed as-is.
+func ScriptKeys(s string) []Key {
+	var keys []Key
+	for _, r := range s {
+		switch r {
+		case '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case '\t':
+			keys = append(keys, Key{Code: KeyTab})
+		case '\x1b':
+			keys = append(keys, Key{Code: KeyEscape})
+		default:
+			keys = append(keys, Key{Code: KeyRune, Rune: r})
+		}
+	}
+	return keys
+}
+
+func (t *ScriptedTerminal) ReadKey() (Key, error) {
+	if len(t.Keys) == 0 {
+		return Key{}, io.EOF
+	}
+	key := t.Keys[0]
+	t.Keys = t.Keys[1:]
+	return key, nil
+}
+
+func (t *ScriptedTerminal) Size() (int, int) {
+	return t.Width, t.Height
+}
+
+func (t *ScriptedTerminal) Write(p []byte) (int, error) {
+	return t.Output.Write(p)
+}
+
+func (t *ScriptedTerminal) Suspend() error { return nil }
+func (t *ScriptedTerminal) Resume() error  { return nil }
+func (t *ScriptedTerminal) Close() error   { return nil }
+
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;?]*[A-Za-z]")
+
+// LastFrame returns the most recently drawn screen as plain text
+func (t *ScriptedTerminal) LastFrame() string {
+	out := t.Output.String()
+	if i := strings.LastIndex(out, frameStart); i >= 0 {
+		out = out[i+len(frameStart):]
+	}
+	out = ansiPattern.ReplaceAllString(out, "")
+	return strings.ReplaceAll(out, "\r\n", "\n")
+}
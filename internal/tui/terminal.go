@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyCode identifies a key press
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyTab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyPageUp
+	KeyPageDown
+	KeyCtrlC
+)
+
+// Key is a single key press; Rune is set for KeyRune
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// Terminal is the screen and keyboard the TUI runs on
+type Terminal interface {
+	// ReadKey blocks until a key is pressed
+	ReadKey() (Key, error)
+	// Size returns the width and height in cells
+	Size() (width, height int)
+	// Write draws raw output, including escape sequences
+	Write(p []byte) (int, error)
+	// Suspend restores the normal terminal, e.g. to run an editor
+	Suspend() error
+	// Resume re-enters full-screen mode after Suspend
+	Resume() error
+	// Close restores the terminal for good
+	Close() error
+}
+
+const (
+	enterScreen = "\033[?1049h\033[?25l"
+	leaveScreen = "\033[?25h\033[?1049l"
+)
+
+// TTY is a Terminal on the controlling terminal (/dev/tty)
+type TTY struct {
+	file    *os.File
+	saved   string
+	pending []byte
+}
+
+// OpenTTY switches the controlling terminal to raw, full-screen mode
+func OpenTTY() (*TTY, error) {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("no terminal available: %w", err)
+	}
+
+	saved, err := stty(f, "-g")
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read terminal settings: %w", err)
+	}
+
+	t := &TTY{file: f, saved: saved}
+	if err := t.Resume(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// ReadKey reads and decodes the next key press
+func (t *TTY) ReadKey() (Key, error) {
+	for {
+		if len(t.pending) > 0 {
+			key, n := decodeKey(t.pending)
+			t.pending = t.pending[n:]
+			return key, nil
+		}
+
+		buf := make([]byte, 64)
+		n, err := t.file.Read(buf)
+		if err != nil {
+			return Key{}, err
+		}
+		t.pending = append(t.pending, buf[:n]...)
+	}
+}
+
+// Size returns the terminal size, falling back to 80x24
+func (t *TTY) Size() (int, int) {
+	output, err := stty(t.file, "size")
+	if err != nil {
+		return 80, 24
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 80, 24
+	}
+	height, err1 := strconv.Atoi(fields[0])
+	width, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || width == 0 || height == 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// Write writes raw output to the terminal
+func (t *TTY) Write(p []byte) (int, error) {
+	return t.file.Write(p)
+}
+
+// Suspend leaves raw mode and the alternate screen
+func (t *TTY) Suspend() error {
+	if _, err := t.file.WriteString(leaveScreen); err != nil {
+		return err
+	}
+	_, err := stty(t.file, t.saved)
+	return err
+}
+
+// Resume enters raw mode and the alternate screen
+func (t *TTY) Resume() error {
+	if _, err := stty(t.file, "raw", "-echo"); err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	_, err := t.file.WriteString(enterScreen)
+	return err
+}
+
+// Close restores the terminal and closes it
+func (t *TTY) Close() error {
+	err := t.Suspend()
+	t.file.Close()
+	return err
+}
+
+// stty runs stty against the given terminal
+func stty(f *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = f
+	output, err := cmd.Output()
+	return strings.TrimSpace(string(output)), err
+}
+
+// decodeKey decodes the first key in buf and returns how many bytes it used
+func decodeKey(buf []byte) (Key, int) {
+	switch buf[0] {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, 1
+	case '\t':
+		return Key{Code: KeyTab}, 1
+	case 127, 8:
+		return Key{Code: KeyBackspace}, 1
+	case 3:
+		return Key{Code: KeyCtrlC}, 1
+	case 27:
+		if len(buf) >= 3 && (buf[1] == '[' || buf[1] == 'O') {
+			switch buf[2] {
+			case 'A':
+				return Key{Code: KeyUp}, 3
+			case 'B':
+				return Key{Code: KeyDown}, 3
+			case 'C':
+				return Key{Code: KeyRight}, 3
+			case 'D':
+				return Key{Code: KeyLeft}, 3
+			case '5', '6':
+				if len(buf) >= 4 && buf[3] == '~' {
+					if buf[2] == '5' {
+						return Key{Code: KeyPageUp}, 4
+					}
+					return Key{Code: KeyPageDown}, 4
+				}
+			}
+			// Unknown sequence: drop it
+			return Key{Code: KeyEscape}, len(buf)
+		}
+		return Key{Code: KeyEscape}, 1
+	}
+
+	r, n := utf8.DecodeRune(buf)
+	return Key{Code: KeyRune, Rune: r}, n
+}
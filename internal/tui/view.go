@@ -0,0 +1,314 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Enigama/git-remarks/internal/format"
+	"github.com/Enigama/git-remarks/internal/remark"
+)
+
+const (
+	frameStart = "\033[H"
+	clearEOL   = "\033[K"
+
+	styleReset   = "\033[0m"
+	styleBold    = "\033[1m"
+	styleDim     = "\033[2m"
+	styleReverse = "\033[7m"
+	styleRed     = format.Red
+	styleGreen   = format.Green
+	styleYellow  = format.Yellow
+	styleCyan    = format.Cyan
+)
+
+const keyHints = "j/k move · tab pane · a add · e edit · r resolve · t type · m move · f filter · b branch · d diff · ? help · q quit"
+
+var helpLines = []string{
+	"Keys",
+	"",
+	"  j/k, ↑/↓      move the selection",
+	"  tab, h/l      switch between commits and remarks",
+	"  a             add a remark to the selected commit",
+	"  e             edit the selected remark in $EDITOR",
+	"  r             resolve the selected remark",
+	"  t             change the type of the selected remark",
+	"  m             move the selected remark to another commit",
+	"  f             filter by type (all → thought → doubt → todo → decision)",
+	"  b             switch between current branch and all branches",
+	"  d             show the selected commit's diff",
+	"  g             reload",
+	"  q             quit",
+	"",
+	"Press any key to continue",
+}
+
+// draw renders the current state to the terminal
+func (a *App) draw() {
+	width, height := a.term.Size()
+
+	var lines []string
+	switch a.mode {
+	case modeDiff:
+		lines = a.renderDiff(width, height)
+	case modeHelp:
+		lines = a.renderHelp(width, height)
+	default:
+		lines = a.renderMain(width, height)
+	}
+
+	var b strings.Builder
+	b.WriteString(frameStart)
+	for i, line := range lines {
+		b.WriteString(line)
+		b.WriteString(clearEOL)
+		if i < len(lines)-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	a.term.Write([]byte(b.String()))
+}
+
+// renderMain renders the commit list, the remarks pane and the status bar
+func (a *App) renderMain(width, height int) []string {
+	rows := height - 3
+	if rows < 1 {
+		rows = 1
+	}
+
+	leftWidth := clamp(width*2/5, 24, 60)
+	if leftWidth > width-10 {
+		leftWidth = width / 2
+	}
+	rightWidth := width - leftWidth - 1
+
+	left := a.renderCommits(leftWidth, rows)
+	right := a.renderRemarks(rightWidth, rows)
+
+	lines := []string{styled(styleReverse, fit(a.title(), width))}
+	for i := 0; i < rows; i++ {
+		lines = append(lines, left[i]+styled(styleDim, "│")+right[i])
+	}
+	lines = append(lines, a.renderStatus(width), styled(styleDim, fit(keyHints, width)))
+	return lines
+}
+
+func (a *App) title() string {
+	scope := a.opts.Branch
+	if a.allBranches {
+		scope = "all branches"
+	}
+	filter := "all types"
+	if a.typeFilter != "" {
+		filter = string(a.typeFilter)
+	}
+
+	total := 0
+	for _, c := range a.commits {
+		total += len(c.Remarks)
+	}
+	return fmt.Sprintf(" git remarks · %s · %s · %d remark%s", scope, filter, total, format.Plural(total))
+}
+
+// renderCommits renders exactly rows lines of the commit list
+func (a *App) renderCommits(width, rows int) []string {
+	offset := scrollOffset(a.commitCursor, rows, len(a.commits))
+
+	lines := make([]string, rows)
+	for i := range lines {
+		n := offset + i
+		if n >= len(a.commits) {
+			lines[i] = fit("", width)
+			continue
+		}
+
+		c := a.commits[n]
+		badge := "    "
+		if len(c.Remarks) > 0 {
+			badge = fmt.Sprintf("(%d) ", len(c.Remarks))
+			if len(badge) < 4 {
+				badge = fmt.Sprintf("%-4s", badge)
+			}
+		}
+		text := fit(fmt.Sprintf(" %s %s%s", c.Info.ShortSHA, badge, c.Info.Subject), width)
+
+		switch {
+		case n == a.commitCursor && (a.focus == paneCommits || a.mode == modeMove):
+			lines[i] = styled(styleReverse, text)
+		case n == a.commitCursor:
+			lines[i] = styled(styleBold, text)
+		case len(c.Remarks) == 0:
+			lines[i] = styled(styleDim, text)
+		default:
+			lines[i] = text
+		}
+	}
+	return lines
+}
+
+// renderRemarks renders exactly rows lines of the selected commit's remarks
+func (a *App) renderRemarks(width, rows int) []string {
+	var content []string
+	selectedLine := 0
+
+	if c := a.selectedCommit(); c != nil {
+		content = append(content, styled(styleBold, fit(" "+c.Info.ShortSHA+" "+c.Info.Subject, width)), fit("", width))
+
+		if len(c.Remarks) == 0 {
+			content = append(content, styled(styleDim, fit(" No remarks. Press a to add one.", width)))
+		}
+
+		for i, r := range c.Remarks {
+			if i == a.remarkCursor {
+				selectedLine = len(content)
+			}
+
+			header := fit(" "+a.remarkHeader(r), width)
+			if i == a.remarkCursor && a.focus == paneRemarks && a.mode != modeMove {
+				content = append(content, styled(styleReverse, header))
+			} else {
+				content = append(content, styled(format.TypeColor(r.Type), header))
+			}
+
+			if r.Anchor != nil {
+				content = append(content, styled(styleDim, fit("   @ "+r.Anchor.String(), width)))
+			}
+			for _, line := range wrap(strings.TrimSpace(r.Body), width-3) {
+				content = append(content, fit("   "+line, width))
+			}
+			content = append(content, fit("", width))
+		}
+	}
+
+	offset := 0
+	if selectedLine >= rows {
+		offset = selectedLine - rows/3
+	}
+
+	lines := make([]string, rows)
+	for i := range lines {
+		if n := offset + i; n < len(content) {
+			lines[i] = content[n]
+		} else {
+			lines[i] = fit("", width)
+		}
+	}
+	return lines
+}
+
+func (a *App) remarkHeader(r remark.Remark) string {
+	header := fmt.Sprintf("[%s] %s · %s", r.ID, r.Type, format.Age(r.CreatedAt))
+	if a.allBranches {
+		header += " · " + r.Branch
+	}
+	return header
+}
+
+func (a *App) renderStatus(width int) string {
+	switch a.mode {
+	case modePrompt:
+		return fit(" "+a.promptLabel+string(a.promptInput)+"█", width)
+	case modeMove:
+		return styled(styleYellow, fit(fmt.Sprintf(" Move [%s]: choose a commit, Enter to move, Esc to cancel", a.moveRemark.ID), width))
+	case modeConfirm:
+		return styled(styleYellow, fit(" "+a.status, width))
+	default:
+		return fit(" "+a.status, width)
+	}
+}
+
+// renderDiff renders the diff of the selected commit
+func (a *App) renderDiff(width, height int) []string {
+	title := " diff"
+	if c := a.selectedCommit(); c != nil {
+		title = fmt.Sprintf(" diff %s %s", c.Info.ShortSHA, c.Info.Subject)
+	}
+
+	lines := []string{styled(styleReverse, fit(title, width))}
+	rows := height - 2
+	for i := 0; i < rows; i++ {
+		n := a.diffOffset + i
+		if n >= len(a.diffLines) {
+			lines = append(lines, fit("", width))
+			continue
+		}
+
+		line := fit(" "+strings.ReplaceAll(a.diffLines[n], "\t", "    "), width)
+		switch {
+		case strings.HasPrefix(a.diffLines[n], "+++"), strings.HasPrefix(a.diffLines[n], "---"):
+			lines = append(lines, styled(styleBold, line))
+		case strings.HasPrefix(a.diffLines[n], "+"):
+			lines = append(lines, styled(styleGreen, line))
+		case strings.HasPrefix(a.diffLines[n], "-"):
+			lines = append(lines, styled(styleRed, line))
+		case strings.HasPrefix(a.diffLines[n], "@@"):
+			lines = append(lines, styled(styleCyan, line))
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return append(lines, styled(styleDim, fit(" j/k scroll · space/b page · q close", width)))
+}
+
+func (a *App) renderHelp(width, height int) []string {
+	lines := []string{styled(styleReverse, fit(" git remarks · help", width))}
+	for i := 0; i < height-1; i++ {
+		if i < len(helpLines) {
+			lines = append(lines, fit(" "+helpLines[i], width))
+		} else {
+			lines = append(lines, fit("", width))
+		}
+	}
+	return lines
+}
+
+// scrollOffset returns the first visible row that keeps cursor on screen
+func scrollOffset(cursor, rows, total int) int {
+	if total <= rows || cursor < rows/2 {
+		return 0
+	}
+	return clamp(cursor-rows/2, 0, total-rows)
+}
+
+// fit truncates or pads s to exactly width cells
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		runes := []rune(s)
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// wrap splits text into lines of at most width runes, breaking at spaces
+func wrap(text string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func styled(style, s string) string {
+	return style + s + styleReset
+}